@@ -0,0 +1,17 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Get the missions policy
+// @Description Get the rules missions are currently checked against
+// @Tags admin
+// @Produce  json
+// @Success 200 {object} missions.Policy
+// @Router /admin/policy [get]
+func (app *application) getPolicy(c *gin.Context) {
+	c.JSON(http.StatusOK, app.missions.Policy)
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary List breeds
+// @Description Get the catalog of breeds accepted for cats
+// @Tags breeds
+// @Accept  json
+// @Produce  json
+// @Success 200 {array} cats.Breed
+// @Router /breeds [get]
+func (app *application) listBreeds(c *gin.Context) {
+	breeds := app.cats.Breeds.All()
+
+	c.JSON(http.StatusOK, breeds)
+
+	slog.Info("Breeds listed", "breeds", len(breeds))
+}
+
+// @Summary Get a breed by ID
+// @Description Get a breed from the catalog by ID
+// @Tags breeds
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Breed ID"
+// @Success 200 {object} cats.Breed
+// @Failure 404 {object} map[string]interface{}
+// @Router /breeds/{id} [get]
+func (app *application) getBreed(c *gin.Context) {
+	id := c.Param("id")
+
+	breed, ok := app.cats.Breeds.Get(id)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("Breed with ID %s doesn't exist", id)})
+		return
+	}
+
+	c.JSON(http.StatusOK, breed)
+
+	slog.Info("Breed returned", "id", id)
+}
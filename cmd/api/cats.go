@@ -7,7 +7,9 @@ import (
 	"log/slog"
 	"net/http"
 	"strconv"
+	"strings"
 
+	"spy-cat-agency/internal/cats"
 	"spy-cat-agency/internal/models"
 	"spy-cat-agency/internal/validator"
 
@@ -15,13 +17,15 @@ import (
 )
 
 // @Summary Create a new cat
-// @Description Create a new spy cat
+// @Description Create a new spy cat, the breed can be given by name or ID in any case.
+// @Description The salary is a decimal amount with at most two decimal places, paid in the ISO 4217 currency (USD by default)
 // @Tags cats
 // @Accept  json
 // @Produce  json
 // @Param cat body models.Cat true "Cat object"
 // @Success 201 {object} map[string]interface{}
 // @Failure 400 {object} map[string]interface{}
+// @Failure 422 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
 // @Router /cats/create [post]
 func (app *application) createCat(c *gin.Context) {
@@ -30,13 +34,25 @@ func (app *application) createCat(c *gin.Context) {
 		cat = &models.Cat{}
 	)
 	if err := c.ShouldBindJSON(cat); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		writeJSONBindError(c, err, "salary")
 		return
 	}
 
+	if cat.Currency == "" {
+		cat.Currency = models.DefaultCurrency
+	}
+	cat.Currency = strings.ToUpper(cat.Currency)
+
 	v.Check(cat.Name != "", "name", validator.ErrEmptyFIeld.Error())
+	v.Check(!cat.Salary.IsNegative(), "salary", "can't be negative")
+	v.Check(cat.Salary <= cats.MaxSalary, "salary", fmt.Sprintf("can't be more than %s", cats.MaxSalary))
+	v.Check(models.ValidCurrency(cat.Currency), "currency", "must be an ISO 4217 currency code")
 	v.Check(cat.Breed != "", "breed", "can't be empty")
-	v.Check(app.cats.Breeds.Exists(cat.Breed), "breed", "invalid breed")
+	if breed, ok := app.cats.Breeds.Lookup(cat.Breed); ok {
+		cat.Breed = breed.Name
+	} else {
+		v.AddError("breed", invalidBreedMessage(app.cats.Breeds.Suggest(cat.Breed, 3)))
+	}
 
 	if !v.Valid() {
 		writeJSONValidationErrors(c, v.Errors)
@@ -54,14 +70,20 @@ func (app *application) createCat(c *gin.Context) {
 }
 
 // @Summary Remove a cat
-// @Description Remove a spy cat by ID
+// @Description Archive a spy cat by ID. A cat on an active mission is only removed if the mission
+// @Description is handed over to a replacement cat or explicitly orphaned, which moves it back to draft.
+// @Description Missions already in progress can't be orphaned
 // @Tags cats
 // @Accept  json
 // @Produce  json
 // @Param id path int true "Cat ID"
+// @Param replacement_id query int false "Cat taking over the active missions"
+// @Param orphan query bool false "Unassign the active missions"
 // @Success 200 {object} map[string]interface{}
 // @Failure 400 {object} map[string]interface{}
 // @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 422 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
 // @Router /cats/remove/{id} [delete]
 func (app *application) removeCat(c *gin.Context) {
@@ -74,56 +96,198 @@ func (app *application) removeCat(c *gin.Context) {
 		return
 	}
 
+	var opts cats.RemoveOptions
+	if replacementID := queryInt(c, v, "replacement_id"); replacementID != nil {
+		opts.ReplacementID = int64(*replacementID)
+		v.Check(opts.ReplacementID > 0, "replacement_id", "must be a positive cat ID")
+	}
+	opts.Orphan = queryBool(c, v, "orphan")
+
 	v.Check(id != 0, "id", validator.ErrZeroID.Error())
+	v.Check(opts.ReplacementID == 0 || !opts.Orphan, "orphan", "can't be combined with replacement_id")
 
 	if !v.Valid() {
 		writeJSONValidationErrors(c, v.Errors)
 		return
 	}
 
-	if err := app.cats.Remove(c, id); err != nil {
+	removal, err := app.cats.Remove(c, id, opts)
+	if err != nil {
 		switch {
 		case errors.Is(err, sql.ErrNoRows):
 			c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("Cat with ID %d doesn't exist", id)})
 			return
+		case errors.Is(err, cats.ErrActiveMission), errors.Is(err, cats.ErrReplacementBusy),
+			errors.Is(err, cats.ErrCannotOrphan):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		case errors.Is(err, cats.ErrReplacementNotFound):
+			writeJSONValidationErrors(c, map[string]string{"replacement_id": err.Error()})
+			return
 		default:
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
 			return
 		}
 	}
 
-	c.JSON(http.StatusOK, gin.H{"info": "success"})
+	c.JSON(http.StatusOK, gin.H{"info": "success", "missions": removal})
 
-	slog.Info("Cat removed", "id", id)
+	slog.Info("Cat removed", "id", id, "reassigned", removal.ReassignedMissions, "orphaned", removal.OrphanedMissions)
+}
+
+// @Summary Restore a cat
+// @Description Bring back a removed spy cat by ID
+// @Tags cats
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Cat ID"
+// @Success 200 {object} models.Cat
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /cats/restore/{id} [put]
+func (app *application) restoreCat(c *gin.Context) {
+	idStr := c.Param("id")
+
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid cat ID"})
+		return
+	}
+
+	v := validator.New()
+	v.Check(id != 0, "id", validator.ErrZeroID.Error())
+	if !v.Valid() {
+		writeJSONValidationErrors(c, v.Errors)
+		return
+	}
+
+	cat, err := app.cats.Restore(c, id)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("Cat with ID %d doesn't exist", id)})
+			return
+		case errors.Is(err, cats.ErrNotRemoved):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, cat)
+
+	slog.Info("Cat restored", "id", id)
+}
+
+// @Summary Update a cat
+// @Description Update a spy cat's name, breed, years of experience or duty status, omitted fields are left untouched.
+// @Description A cat on an active mission can't go on leave or retire
+// @Tags cats
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Cat ID"
+// @Param cat body cats.Patch true "Fields to update"
+// @Success 200 {object} models.Cat
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 422 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /cats/update/{id} [patch]
+func (app *application) updateCat(c *gin.Context) {
+	idStr := c.Param("id")
+
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid cat ID"})
+		return
+	}
+
+	var patch cats.Patch
+	if err := c.ShouldBindJSON(&patch); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	v := validator.New()
+	v.Check(id != 0, "id", validator.ErrZeroID.Error())
+	v.Check(!patch.Empty(), "body", "no fields to update")
+	if patch.Name != nil {
+		v.Check(*patch.Name != "", "name", validator.ErrEmptyFIeld.Error())
+	}
+	if patch.YearsOfExperience != nil {
+		v.Check(*patch.YearsOfExperience >= 0, "yoe", "can't be negative")
+	}
+	if patch.DutyStatus != nil {
+		v.Check(cats.ValidDutyStatus(*patch.DutyStatus), "duty_status", "must be active, on_leave or retired")
+	}
+	if patch.Breed != nil {
+		if breed, ok := app.cats.Breeds.Lookup(*patch.Breed); ok {
+			patch.Breed = &breed.Name
+		} else {
+			v.AddError("breed", invalidBreedMessage(app.cats.Breeds.Suggest(*patch.Breed, 3)))
+		}
+	}
+	if !v.Valid() {
+		writeJSONValidationErrors(c, v.Errors)
+		return
+	}
+
+	cat, err := app.cats.Update(c, id, patch)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("Cat with ID %d doesn't exist", id)})
+			return
+		case errors.Is(err, cats.ErrOnMission):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, cat)
+
+	slog.Info("Cat updated", "id", id)
 }
 
 // @Summary Update a cat's salary
-// @Description Update a spy cat's salary by ID
+// @Description Update a spy cat's salary by ID, the change is recorded in the salary history
 // @Tags cats
 // @Accept  json
 // @Produce  json
-// @Param cat body models.Cat true "Cat object"
+// @Param update body cats.SalaryUpdate true "Salary update"
 // @Success 200 {object} map[string]interface{}
 // @Failure 400 {object} map[string]interface{}
 // @Failure 404 {object} map[string]interface{}
+// @Failure 422 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
 // @Router /cats/update_salary [put]
 func (app *application) updateCatsSalary(c *gin.Context) {
-	cat := &models.Cat{}
-	if err := c.ShouldBindJSON(&cat); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+	var update cats.SalaryUpdate
+	if err := c.ShouldBindJSON(&update); err != nil {
+		writeJSONBindError(c, err, "salary")
 		return
 	}
 
 	v := validator.New()
-	v.Check(cat.Salary != 0, "salary", validator.ErrEmptyFIeld.Error())
-	v.Check(cat.ID != 0, "id", validator.ErrZeroID.Error())
+	v.Check(update.Salary != 0, "salary", validator.ErrEmptyFIeld.Error())
+	v.Check(!update.Salary.IsNegative(), "salary", "can't be negative")
+	v.Check(update.Salary <= cats.MaxSalary, "salary", fmt.Sprintf("can't be more than %s", cats.MaxSalary))
+	v.Check(update.ID != 0, "id", validator.ErrZeroID.Error())
+	v.Check(update.Actor != "", "actor", validator.ErrEmptyFIeld.Error())
 	if !v.Valid() {
 		writeJSONValidationErrors(c, v.Errors)
 		return
 	}
 
-	updatedCat, err := app.cats.UpdateSalary(c, cat)
+	change, err := app.cats.UpdateSalary(c, update)
 	if err != nil {
 		switch {
 		case errors.Is(err, sql.ErrNoRows):
@@ -135,39 +299,199 @@ func (app *application) updateCatsSalary(c *gin.Context) {
 		}
 	}
 
-	c.JSON(http.StatusOK, gin.H{"info": "succes", "updated cat": updatedCat})
+	updatedCat := models.Cat{ID: change.CatID, Salary: change.NewSalary, Currency: change.Currency}
+	c.JSON(http.StatusOK, gin.H{"info": "succes", "updated cat": updatedCat, "change": change})
+
+	slog.Info("Cat's salary updated", "id", change.CatID, "before", change.OldSalary, "after", change.NewSalary, "actor", change.Actor)
+}
+
+// @Summary Get a cat's salary history
+// @Description Get all salary changes of a spy cat, oldest first
+// @Tags cats
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Cat ID"
+// @Success 200 {array} models.SalaryChange
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /cats/salary_history/{id} [get]
+func (app *application) getSalaryHistory(c *gin.Context) {
+	idStr := c.Param("id")
+
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid cat ID"})
+		return
+	}
+
+	v := validator.New()
+	v.Check(id != 0, "id", validator.ErrZeroID.Error())
+	if !v.Valid() {
+		writeJSONValidationErrors(c, v.Errors)
+		return
+	}
+
+	changes, err := app.cats.SalaryHistory(c, id)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("Cat with ID %d doesn't exist", id)})
+			return
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, changes)
+
+	slog.Info("Salary history returned", "id", id, "changes", len(changes))
+}
+
+// @Summary List salary changes
+// @Description Get the salary changes of all spy cats within a date range, oldest first
+// @Tags cats
+// @Accept  json
+// @Produce  json
+// @Param from query string true "Changed at or after (RFC 3339 or YYYY-MM-DD)"
+// @Param to query string true "Changed before (RFC 3339 or YYYY-MM-DD)"
+// @Success 200 {array} models.SalaryChange
+// @Failure 422 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /cats/salary_changes [get]
+func (app *application) listSalaryChanges(c *gin.Context) {
+	v := validator.New()
+	from := queryTime(c, v, "from")
+	to := queryTime(c, v, "to")
+	v.Check(from != nil, "from", validator.ErrEmptyFIeld.Error())
+	v.Check(to != nil, "to", validator.ErrEmptyFIeld.Error())
+	if from != nil && to != nil {
+		v.Check(from.Before(*to), "from", "must be before to")
+	}
+	if !v.Valid() {
+		writeJSONValidationErrors(c, v.Errors)
+		return
+	}
+
+	changes, err := app.cats.SalaryChanges(c, *from, *to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
 
-	slog.Info("Cat's salary updated", "id", cat.ID, "before", cat.Salary, "after", updatedCat.Salary)
+	c.JSON(http.StatusOK, changes)
+
+	slog.Info("Salary changes returned", "from", from, "to", to, "changes", len(changes))
 }
 
-// @Summary List all cats
-// @Description Get a list of all spy cats
+// @Summary List cats
+// @Description Get a page of spy cats, filtered and sorted
 // @Tags cats
 // @Accept  json
 // @Produce  json
-// @Success 200 {array} models.Cat
+// @Param breed query string false "Breed"
+// @Param name query string false "Name prefix"
+// @Param status query string false "Status: available, on_mission, on_leave or retired"
+// @Param min_salary query number false "Minimum salary"
+// @Param max_salary query number false "Maximum salary"
+// @Param min_yoe query int false "Minimum years of experience"
+// @Param max_yoe query int false "Maximum years of experience"
+// @Param include_deleted query bool false "Also list removed cats"
+// @Param sort query string false "Sort key: id, name, salary or yoe, prefixed with - for descending order"
+// @Param cursor query string false "Cursor of the next page"
+// @Param limit query int false "Page size"
+// @Success 200 {object} cats.Page
+// @Failure 422 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
 // @Router /cats/list [get]
 func (app *application) listCats(c *gin.Context) {
-	cats, err := app.cats.List(c)
+	v := validator.New()
+	filter := app.catsFilter(c, v)
+	filter.Status = models.CatStatus(c.Query("status"))
+
+	app.writeCatsPage(c, v, filter)
+}
+
+// @Summary List available cats
+// @Description Get a page of spy cats that are on duty and not on a mission, filtered and sorted
+// @Tags cats
+// @Accept  json
+// @Produce  json
+// @Param breed query string false "Breed"
+// @Param name query string false "Name prefix"
+// @Param min_salary query number false "Minimum salary"
+// @Param max_salary query number false "Maximum salary"
+// @Param min_yoe query int false "Minimum years of experience"
+// @Param max_yoe query int false "Maximum years of experience"
+// @Param sort query string false "Sort key: id, name, salary or yoe, prefixed with - for descending order"
+// @Param cursor query string false "Cursor of the next page"
+// @Param limit query int false "Page size"
+// @Success 200 {object} cats.Page
+// @Failure 422 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /cats/available [get]
+func (app *application) listAvailableCats(c *gin.Context) {
+	v := validator.New()
+	filter := app.catsFilter(c, v)
+	filter.Status = models.CatAvailable
+	filter.IncludeDeleted = false
+
+	app.writeCatsPage(c, v, filter)
+}
+
+// catsFilter reads the cats filter from the query, recording malformed parameters in v.
+func (app *application) catsFilter(c *gin.Context, v *validator.Validator) cats.Filter {
+	filter := cats.Filter{
+		Breed:          c.Query("breed"),
+		NamePrefix:     c.Query("name"),
+		MinSalary:      queryMoney(c, v, "min_salary"),
+		MaxSalary:      queryMoney(c, v, "max_salary"),
+		MinExperience:  queryInt(c, v, "min_yoe"),
+		MaxExperience:  queryInt(c, v, "max_yoe"),
+		IncludeDeleted: queryBool(c, v, "include_deleted"),
+		Sort:           c.Query("sort"),
+		Cursor:         c.Query("cursor"),
+	}
+	if limit := queryInt(c, v, "limit"); limit != nil {
+		filter.Limit = *limit
+	}
+	if breed, ok := app.cats.Breeds.Lookup(filter.Breed); ok {
+		filter.Breed = breed.Name
+	}
+
+	return filter
+}
+
+func (app *application) writeCatsPage(c *gin.Context, v *validator.Validator, filter cats.Filter) {
+	filter.Validate(v)
+	if !v.Valid() {
+		writeJSONValidationErrors(c, v.Errors)
+		return
+	}
+
+	page, err := app.cats.List(c, filter)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Unexpected error while fetching cats, try again later"})
 		return
 	}
 
-	c.JSON(http.StatusOK, cats)
+	c.JSON(http.StatusOK, page)
 
-	slog.Info("Cats listed", "cats", len(cats))
+	slog.Info("Cats listed", "status", filter.Status, "cats", len(page.Cats), "total", page.Total)
 }
 
 // @Summary Get a cat by ID
-// @Description Get a spy cat by ID
+// @Description Get a spy cat by ID, removed cats are only returned with include_deleted
 // @Tags cats
 // @Accept  json
 // @Produce  json
 // @Param id path int true "Cat ID"
+// @Param include_deleted query bool false "Also return a removed cat"
 // @Success 200 {object} models.Cat
 // @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 422 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
 // @Router /cats/get/{id} [get]
 func (app *application) getCat(c *gin.Context) {
@@ -181,16 +505,17 @@ func (app *application) getCat(c *gin.Context) {
 
 	v := validator.New()
 	v.Check(id != 0, "id", validator.ErrZeroID.Error())
+	withDeleted := queryBool(c, v, "include_deleted")
 	if !v.Valid() {
 		writeJSONValidationErrors(c, v.Errors)
 		return
 	}
 
-	cat, err := app.cats.Get(c, id)
+	cat, err := app.cats.Get(c, id, withDeleted)
 	if err != nil {
 		switch {
 		case errors.Is(err, sql.ErrNoRows):
-			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Cat with ID %d doesn't exist", id)})
+			c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("Cat with ID %d doesn't exist", id)})
 			return
 		default:
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
@@ -202,3 +527,12 @@ func (app *application) getCat(c *gin.Context) {
 
 	slog.Info("Cat returned", "id", id)
 }
+
+// invalidBreedMessage builds the validation message for an unknown breed.
+func invalidBreedMessage(suggestions []string) string {
+	if len(suggestions) == 0 {
+		return "invalid breed"
+	}
+
+	return "invalid breed, did you mean: " + strings.Join(suggestions, ", ")
+}
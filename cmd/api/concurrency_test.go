@@ -0,0 +1,177 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"spy-cat-agency/internal/missions"
+	"spy-cat-agency/internal/models"
+	"spy-cat-agency/internal/storage/storagetest"
+
+	"github.com/gin-gonic/gin"
+)
+
+// workers is how many requests race each other in the tests below.
+const workers = 10
+
+// testApp serves the missions API over a fresh database.
+func testApp(t *testing.T) (http.Handler, *sql.DB) {
+	t.Helper()
+
+	db := storagetest.Open(t)
+	db.SetMaxOpenConns(workers * 2)
+
+	gin.SetMode(gin.TestMode)
+	app := &application{
+		missions: missions.NewService(missions.NewRepository(db), missions.DefaultPolicy()),
+	}
+
+	return app.routes(), db
+}
+
+// raceRequests sends body to the route from workers goroutines at once and
+// returns the response status codes.
+func raceRequests(t *testing.T, h http.Handler, method, path string, body func(i int) any) []int {
+	t.Helper()
+
+	payloads := make([][]byte, workers)
+	for i := range payloads {
+		payload, err := json.Marshal(body(i))
+		if err != nil {
+			t.Fatal(err)
+		}
+		payloads[i] = payload
+	}
+
+	var (
+		wg    sync.WaitGroup
+		start = make(chan struct{})
+		codes = make([]int, workers)
+	)
+	for i := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req := httptest.NewRequest(method, path, bytes.NewReader(payloads[i]))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			<-start
+			h.ServeHTTP(rec, req)
+			codes[i] = rec.Code
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	return codes
+}
+
+func createTestMission(t *testing.T, db *sql.DB) int {
+	t.Helper()
+
+	mission := &models.Mission{
+		Targets: []models.Target{{Name: "Target", Country: "FR"}},
+	}
+	created, err := missions.NewRepository(db).Create(context.Background(), mission, missions.DefaultPolicy())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return created.ID
+}
+
+func TestAddTargetsConcurrently(t *testing.T) {
+	h, db := testApp(t)
+	maxTargets := missions.DefaultPolicy().MaxTargets
+	missionID := createTestMission(t, db)
+
+	codes := raceRequests(t, h, http.MethodPut, "/missions/add_targets", func(i int) any {
+		return models.Mission{
+			ID:      missionID,
+			Targets: []models.Target{{ID: 1, Name: fmt.Sprintf("Extra %d", i), Country: "DE"}},
+		}
+	})
+
+	added, rejected := 0, 0
+	for _, code := range codes {
+		switch code {
+		case http.StatusOK:
+			added++
+		case http.StatusUnprocessableEntity:
+			rejected++
+		default:
+			t.Errorf("add targets responded %d", code)
+		}
+	}
+	if want := maxTargets - 1; added != want || rejected != workers-want {
+		t.Errorf("added %d and rejected %d, want %d and %d", added, rejected, want, workers-want)
+	}
+
+	var count int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM targets WHERE mission_id = $1`, missionID).Scan(&count); err != nil {
+		t.Fatal(err)
+	}
+	if count != maxTargets {
+		t.Errorf("mission has %d targets, want %d", count, maxTargets)
+	}
+}
+
+func TestAssignCatConcurrently(t *testing.T) {
+	h, db := testApp(t)
+
+	var catID int
+	catQuery := `
+		INSERT INTO cats (name, years_of_experience, breed, salary)
+		VALUES ('Tom', 3, 'Siamese', 100) RETURNING id
+	`
+	if err := db.QueryRow(catQuery).Scan(&catID); err != nil {
+		t.Fatal(err)
+	}
+
+	missionIDs := make([]int, workers)
+	for i := range missionIDs {
+		missionIDs[i] = createTestMission(t, db)
+	}
+
+	codes := raceRequests(t, h, http.MethodPut, "/missions/assign", func(i int) any {
+		return models.Mission{ID: missionIDs[i], CatID: catID}
+	})
+
+	assigned, busy := 0, 0
+	for _, code := range codes {
+		switch code {
+		case http.StatusOK:
+			assigned++
+		case http.StatusConflict:
+			busy++
+		default:
+			t.Errorf("assign responded %d", code)
+		}
+	}
+	if assigned != 1 || busy != workers-1 {
+		t.Errorf("assigned %d and refused %d, want 1 and %d", assigned, busy, workers-1)
+	}
+
+	var active, open int
+	activeQuery := `
+		SELECT COUNT(*) FROM missions WHERE cat_id = $1 AND status IN ('assigned', 'in_progress')
+	`
+	if err := db.QueryRow(activeQuery, catID).Scan(&active); err != nil {
+		t.Fatal(err)
+	}
+	openQuery := `
+		SELECT COUNT(*) FROM mission_assignments WHERE cat_id = $1 AND unassigned_at IS NULL
+	`
+	if err := db.QueryRow(openQuery, catID).Scan(&open); err != nil {
+		t.Fatal(err)
+	}
+	if active != 1 || open != 1 {
+		t.Errorf("cat has %d active missions and %d open assignments, want 1 and 1", active, open)
+	}
+}
@@ -3,6 +3,11 @@ package main
 import (
 	"errors"
 	"net/http"
+	"strconv"
+	"time"
+
+	"spy-cat-agency/internal/models"
+	"spy-cat-agency/internal/validator"
 
 	"github.com/gin-gonic/gin"
 )
@@ -12,3 +17,80 @@ var InternalServerError = errors.New("Internal server error")
 func writeJSONValidationErrors(c *gin.Context, errs map[string]string) {
 	c.JSON(http.StatusUnprocessableEntity, gin.H{"errors": errs})
 }
+
+// writeJSONBindError responds to a request body that couldn't be read.
+// Malformed amounts are validation errors of the given field, anything else is a bad request.
+func writeJSONBindError(c *gin.Context, err error, moneyField string) {
+	var moneyErr *models.MoneyError
+	if errors.As(err, &moneyErr) {
+		writeJSONValidationErrors(c, map[string]string{moneyField: moneyErr.Error()})
+		return
+	}
+
+	c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+}
+
+// queryInt reads an optional integer query parameter, recording an error in v if it is malformed.
+func queryInt(c *gin.Context, v *validator.Validator, key string) *int {
+	raw, ok := c.GetQuery(key)
+	if !ok || raw == "" {
+		return nil
+	}
+
+	n, err := strconv.Atoi(raw)
+	if err != nil {
+		v.AddError(key, "must be an integer")
+		return nil
+	}
+
+	return &n
+}
+
+// queryBool reads an optional boolean query parameter, recording an error in v if it is malformed.
+func queryBool(c *gin.Context, v *validator.Validator, key string) bool {
+	raw, ok := c.GetQuery(key)
+	if !ok || raw == "" {
+		return false
+	}
+
+	b, err := strconv.ParseBool(raw)
+	if err != nil {
+		v.AddError(key, "must be true or false")
+		return false
+	}
+
+	return b
+}
+
+// queryMoney reads an optional amount query parameter, recording an error in v if it is malformed.
+func queryMoney(c *gin.Context, v *validator.Validator, key string) *models.Money {
+	raw, ok := c.GetQuery(key)
+	if !ok || raw == "" {
+		return nil
+	}
+
+	m, err := models.ParseMoney(raw)
+	if err != nil {
+		v.AddError(key, err.Error())
+		return nil
+	}
+
+	return &m
+}
+
+// queryTime reads an optional RFC 3339 or YYYY-MM-DD query parameter, recording an error in v if it is malformed.
+func queryTime(c *gin.Context, v *validator.Validator, key string) *time.Time {
+	raw, ok := c.GetQuery(key)
+	if !ok || raw == "" {
+		return nil
+	}
+
+	for _, layout := range []string{time.RFC3339, time.DateOnly} {
+		if t, err := time.Parse(layout, raw); err == nil {
+			return &t
+		}
+	}
+
+	v.AddError(key, "must be an RFC 3339 timestamp or a YYYY-MM-DD date")
+	return nil
+}
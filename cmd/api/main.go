@@ -1,13 +1,16 @@
 package main
 
 import (
+	"context"
 	"log"
 	"log/slog"
 	"os"
+	"time"
 
 	"spy-cat-agency/internal/cats"
 	"spy-cat-agency/internal/env"
 	"spy-cat-agency/internal/missions"
+	"spy-cat-agency/internal/payroll"
 	"spy-cat-agency/internal/storage"
 	"spy-cat-agency/internal/validator"
 
@@ -34,22 +37,39 @@ import (
 // @host localhost:7777
 // @BasePath /
 type config struct {
-	port      string
-	breedsApi string
-	db        storage.Config
+	port            string
+	breeds          cats.BreedsConfig
+	bonuses         payroll.Bonuses
+	policy          missions.Policy
+	overdueInterval time.Duration
+	db              storage.Config
 }
 
 type application struct {
 	config
 	cats     *cats.Service
 	missions *missions.Service
+	payroll  *payroll.Service
 	valid    *validator.Validator
 }
 
 func main() {
 	cfg := config{
-		port:      env.GetString("SPY_CAT_AGENCY_PORT", ":7777"),
-		breedsApi: env.GetString("CATS_BREEDS_API", "https://api.thecatapi.com/v1/breeds"),
+		port: env.GetString("SPY_CAT_AGENCY_PORT", ":7777"),
+		breeds: cats.BreedsConfig{
+			Provider:        env.GetString("CATS_BREEDS_PROVIDER", cats.ProviderAPI),
+			File:            env.GetString("CATS_BREEDS_FILE", "breeds.yaml"),
+			Api:             env.GetString("CATS_BREEDS_API", "https://api.thecatapi.com/v1/breeds"),
+			RefreshInterval: env.GetDuration("CATS_BREEDS_REFRESH_INTERVAL", time.Hour),
+			SnapshotPath:    env.GetString("CATS_BREEDS_SNAPSHOT", "data/breeds.json"),
+			MaxRetries:      env.GetInt("CATS_BREEDS_MAX_RETRIES", 5),
+		},
+		policy: missions.Policy{
+			MaxTargets:              env.GetInt("MISSIONS_MAX_TARGETS", missions.DefaultPolicy().MaxTargets),
+			AllowDeleteAssigned:     env.GetBool("MISSIONS_ALLOW_DELETE_ASSIGNED", false),
+			AllowCompleteUnassigned: env.GetBool("MISSIONS_ALLOW_COMPLETE_UNASSIGNED", false),
+		},
+		overdueInterval: env.GetDuration("MISSIONS_OVERDUE_INTERVAL", time.Minute),
 		db: storage.Config{
 			Dsn:          env.GetString("DB_DSN", ""),
 			MaxIdleTime:  env.GetString("DB_MAX_IDLE_TIME", "15m"),
@@ -58,6 +78,19 @@ func main() {
 		},
 	}
 
+	bonuses, err := payroll.ParseBonuses(
+		env.GetString("PAYROLL_MISSION_BONUS", ""),
+		env.GetString("PAYROLL_TARGET_BONUS", ""),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+	cfg.bonuses = bonuses
+
+	if err := cfg.policy.Validate(); err != nil {
+		log.Fatal(err)
+	}
+
 	db, err := storage.ConnectSQL(cfg.db)
 	if err != nil {
 		panic(err)
@@ -73,21 +106,32 @@ func main() {
 	}
 	slog.Info("MIgrations applied")
 
-	breeds, err := cats.NewBreeds(cfg.breedsApi)
+	breedProvider, err := cats.NewBreedProvider(cfg.breeds, db)
 	if err != nil {
-		panic(err)
+		log.Fatal(err)
 	}
 
+	breeds := cats.NewBreeds(breedProvider, cfg.breeds)
+	if err := breeds.Load(context.Background()); err != nil {
+		slog.Error("Breeds cache is empty, breeds will be rejected until the next refresh", "error", err)
+	}
+	go breeds.Run(context.Background())
+
 	catsRepo := cats.NewRepository(db)
 	catsService := cats.NewService(catsRepo, breeds)
 
 	missionsRepo := missions.NewRepository(db)
-	missionsService := missions.NewService(missionsRepo)
+	missionsService := missions.NewService(missionsRepo, cfg.policy)
+	go missionsService.WatchOverdue(context.Background(), cfg.overdueInterval)
+
+	payrollRepo := payroll.NewRepository(db)
+	payrollService := payroll.NewService(payrollRepo, cfg.bonuses)
 
 	app := &application{
 		config:   cfg,
 		cats:     catsService,
 		missions: missionsService,
+		payroll:  payrollService,
 		valid:    validator.New(),
 	}
 
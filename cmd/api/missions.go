@@ -1,13 +1,17 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
+	"spy-cat-agency/internal/countries"
 	"spy-cat-agency/internal/missions"
 	"spy-cat-agency/internal/models"
 	"spy-cat-agency/internal/validator"
@@ -16,13 +20,16 @@ import (
 )
 
 // @Summary Create a new mission
-// @Description Create a new mission
+// @Description Create a new mission, assigned to a cat if cat_id is set, or as a draft otherwise
 // @Tags missions
 // @Accept  json
 // @Produce  json
 // @Param mission body models.Mission true "Mission object"
 // @Success 201 {object} models.Mission
 // @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 422 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
 // @Router /missions/create [post]
 func (app *application) createMission(c *gin.Context) {
@@ -35,11 +42,16 @@ func (app *application) createMission(c *gin.Context) {
 
 	v := validator.New()
 	v.Check(mission.ID != 0, "id", validator.ErrZeroID.Error())
-	v.Check(mission.CatID != 0, "cat_id", validator.ErrZeroID.Error())
-	for _, target := range mission.Targets {
+	v.Check(mission.CatID >= 0, "cat_id", "can't be negative")
+	checkDueAt(v, &mission.DueAt)
+	for i, target := range mission.Targets {
 		v.Check(target.ID != 0, "id", validator.ErrZeroID.Error())
-		v.Check(target.Country != "", "country", validator.ErrEmptyFIeld.Error())
+		checkCountry(v, &mission.Targets[i].Country)
 		v.Check(target.Name != "", "name", validator.ErrEmptyFIeld.Error())
+		for j := range target.Notes {
+			checkNote(v, &target.Notes[j])
+		}
+		checkDueAt(v, &mission.Targets[i].DueAt)
 	}
 
 	if !v.Valid() {
@@ -49,8 +61,20 @@ func (app *application) createMission(c *gin.Context) {
 
 	newMission, err := app.missions.Create(c, &mission)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
-		return
+		switch {
+		case errors.Is(err, missions.ErrCatNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("Cat with id %d doesn't exist", mission.CatID)})
+			return
+		case errors.Is(err, missions.ErrCatUnavailable), errors.Is(err, missions.ErrCatBusy):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		case errors.Is(err, missions.ErrTooManyTargets):
+			writeJSONValidationErrors(c, map[string]string{"targets": err.Error()})
+			return
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+			return
+		}
 	}
 
 	c.JSON(http.StatusCreated, newMission)
@@ -58,6 +82,122 @@ func (app *application) createMission(c *gin.Context) {
 	slog.Info("Mission created", "id", newMission.ID)
 }
 
+// @Summary Create a mission from a template
+// @Description Create a mission with the targets and default notes of a template version
+// @Tags missions
+// @Accept  json
+// @Produce  json
+// @Param from body missions.FromTemplate true "Template version, optional cat and due date, and the author of the template notes"
+// @Success 201 {object} models.Mission
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 422 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /missions/from_template [post]
+func (app *application) createMissionFromTemplate(c *gin.Context) {
+	var from missions.FromTemplate
+
+	if err := c.ShouldBindJSON(&from); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	v := validator.New()
+	v.Check(from.TemplateID != 0, "template_id", validator.ErrZeroID.Error())
+	checkDueAt(v, &from.DueAt)
+	checkAuthor(v, &from.Author)
+	if !v.Valid() {
+		writeJSONValidationErrors(c, v.Errors)
+		return
+	}
+
+	newMission, err := app.missions.CreateFromTemplate(c, from)
+	if err != nil {
+		switch {
+		case errors.Is(err, missions.ErrTemplateNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("Template with ID %d doesn't exist", from.TemplateID)})
+			return
+		case errors.Is(err, missions.ErrCatNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("Cat with id %d doesn't exist", from.CatID)})
+			return
+		case errors.Is(err, missions.ErrCatUnavailable), errors.Is(err, missions.ErrCatBusy):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		case errors.Is(err, missions.ErrTooManyTargets):
+			writeJSONValidationErrors(c, map[string]string{"template_id": err.Error()})
+			return
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+			return
+		}
+	}
+
+	c.JSON(http.StatusCreated, newMission)
+
+	slog.Info("Mission created from a template", "id", newMission.ID, "template id", from.TemplateID)
+}
+
+// @Summary Clone a mission
+// @Description Create a mission with the targets of another one, completed and aborted missions included
+// @Tags missions
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Mission ID"
+// @Param keep_notes query bool false "Copy the notes of the targets"
+// @Param keep_cat query bool false "Assign the clone to the same cat"
+// @Success 201 {object} models.Mission
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 422 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /missions/clone/{id} [post]
+func (app *application) cloneMission(c *gin.Context) {
+	idStr := c.Param("id")
+
+	id, err := strconv.Atoi(idStr)
+	if err != nil || id == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid mission ID"})
+		return
+	}
+
+	v := validator.New()
+	opts := missions.CloneOptions{
+		KeepNotes: queryBool(c, v, "keep_notes"),
+		KeepCat:   queryBool(c, v, "keep_cat"),
+	}
+	if !v.Valid() {
+		writeJSONValidationErrors(c, v.Errors)
+		return
+	}
+
+	clone, err := app.missions.Clone(c, id, opts)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("Mission with ID %d doesn't exist", id)})
+			return
+		case errors.Is(err, missions.ErrCatNotFound):
+			c.JSON(http.StatusConflict, gin.H{"error": "Cannot keep the cat, it was removed"})
+			return
+		case errors.Is(err, missions.ErrCatUnavailable), errors.Is(err, missions.ErrCatBusy):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		case errors.Is(err, missions.ErrTooManyTargets):
+			writeJSONValidationErrors(c, map[string]string{"targets": err.Error()})
+			return
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+			return
+		}
+	}
+
+	c.JSON(http.StatusCreated, clone)
+
+	slog.Info("Mission cloned", "id", clone.ID, "source id", id)
+}
+
 // @Summary Delete a mission
 // @Description Delete a mission by ID
 // @Tags missions
@@ -67,6 +207,7 @@ func (app *application) createMission(c *gin.Context) {
 // @Success 200 {object} map[string]interface{}
 // @Failure 400 {object} map[string]interface{}
 // @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
 // @Router /missions/delete/{id} [delete]
 func (app *application) deleteMission(c *gin.Context) {
@@ -90,6 +231,9 @@ func (app *application) deleteMission(c *gin.Context) {
 		case errors.Is(err, sql.ErrNoRows):
 			c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("Mission with ID %d doesn't exist", id)})
 			return
+		case errors.Is(err, missions.ErrMissionAssigned):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
 		default:
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
 			return
@@ -101,8 +245,24 @@ func (app *application) deleteMission(c *gin.Context) {
 	slog.Info("Mission deleted", "id", id)
 }
 
+// @Summary Start a mission
+// @Description Move an assigned mission into progress
+// @Tags missions
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Mission ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /missions/start/{id} [put]
+func (app *application) startMission(c *gin.Context) {
+	app.changeMissionStatus(c, app.missions.Start, "Mission started")
+}
+
 // @Summary Complete a mission
-// @Description Mark a mission as completed
+// @Description Mark a mission in progress as completed, all of its targets must be completed
 // @Tags missions
 // @Accept  json
 // @Produce  json
@@ -110,9 +270,47 @@ func (app *application) deleteMission(c *gin.Context) {
 // @Success 200 {object} map[string]interface{}
 // @Failure 400 {object} map[string]interface{}
 // @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
 // @Router /missions/complete/{id} [put]
 func (app *application) completeMission(c *gin.Context) {
+	app.changeMissionStatus(c, app.missions.Complete, "Mission marked as completed")
+}
+
+// @Summary Abort a mission
+// @Description Abort a mission that isn't completed yet
+// @Tags missions
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Mission ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /missions/abort/{id} [put]
+func (app *application) abortMission(c *gin.Context) {
+	app.changeMissionStatus(c, app.missions.Abort, "Mission aborted")
+}
+
+// @Summary Unassign a mission's cat
+// @Description Take the cat off an assigned mission, the mission goes back to draft
+// @Tags missions
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Mission ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /missions/unassign/{id} [put]
+func (app *application) unassignCat(c *gin.Context) {
+	app.changeMissionStatus(c, app.missions.Unassign, "Cat unassigned from a mission")
+}
+
+// changeMissionStatus runs a mission status transition for the mission ID in the path.
+func (app *application) changeMissionStatus(c *gin.Context, change func(context.Context, int) error, logMsg string) {
 	idStr := c.Param("id")
 
 	id, err := strconv.Atoi(idStr)
@@ -128,11 +326,17 @@ func (app *application) completeMission(c *gin.Context) {
 		return
 	}
 
-	if err := app.missions.UpdateAsCompleted(c, id); err != nil {
+	if err := change(c, id); err != nil {
 		switch {
 		case errors.Is(err, sql.ErrNoRows):
 			c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("Mission with ID %d doesn't exist", id)})
 			return
+		case errors.Is(err, missions.ErrInvalidTransition), errors.Is(err, missions.ErrNotAssigned):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		case errors.Is(err, missions.ErrOpenTargets):
+			c.JSON(http.StatusConflict, gin.H{"error": "Cannot complete mission, not all targets are completed"})
+			return
 		default:
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
 			return
@@ -141,47 +345,60 @@ func (app *application) completeMission(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"info": "success"})
 
-	slog.Info("Mission marked as completed", "id", id)
+	slog.Info(logMsg, "id", id)
 }
 
-// @Summary Update target notes
-// @Description Update the notes for a target
+// @Summary Add a target note
+// @Description Append an entry to a target's notes journal
 // @Tags missions
 // @Accept  json
 // @Produce  json
-// @Param target body models.Target true "Target object"
-// @Success 200 {object} map[string]interface{}
+// @Param id path int true "Target ID"
+// @Param note body models.Note true "Note object"
+// @Success 201 {object} models.Note
 // @Failure 400 {object} map[string]interface{}
 // @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
 // @Failure 422 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
-// @Router /missions/update_notes [put]
-func (app *application) updateTargetNotes(c *gin.Context) {
-	var target models.Target
+// @Router /missions/add_note/{id} [post]
+func (app *application) addTargetNote(c *gin.Context) {
+	idStr := c.Param("id")
+
+	id, err := strconv.Atoi(idStr)
+	if err != nil || id == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid target ID"})
+		return
+	}
 
-	if err := c.ShouldBindJSON(&target); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid target body"})
+	var note models.Note
+
+	if err := c.ShouldBindJSON(&note); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid note body"})
 		return
 	}
 
 	v := validator.New()
-	v.Check(target.ID != 0, "id", validator.ErrZeroID.Error())
-	v.Check(target.Notes != "", "notes", validator.ErrEmptyFIeld.Error())
+	checkNote(v, &note)
 	if !v.Valid() {
 		writeJSONValidationErrors(c, v.Errors)
 		return
 	}
 
-	if err := app.missions.UpdateTargetNotes(c, target.ID, target.Notes); err != nil {
+	newNote, err := app.missions.AddNote(c, id, note)
+	if err != nil {
 		switch {
 		case errors.Is(err, sql.ErrNoRows):
-			c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("Target with ID %d doesn't exist", target.ID)})
+			c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("Target with ID %d doesn't exist", id)})
 			return
 		case errors.Is(err, missions.ErrMIssionCompleted):
-			c.JSON(http.StatusNotFound, gin.H{"error": "cannot update, mission is already completed"})
+			c.JSON(http.StatusConflict, gin.H{"error": "Cannot add note, mission is already completed"})
+			return
+		case errors.Is(err, missions.ErrMissionAborted):
+			c.JSON(http.StatusConflict, gin.H{"error": "Cannot add note, mission is aborted"})
 			return
 		case errors.Is(err, missions.ErrTargetCompleted):
-			c.JSON(http.StatusNotFound, gin.H{"error": "cannot update, target is already completed"})
+			c.JSON(http.StatusConflict, gin.H{"error": "Cannot add note, target is already completed"})
 			return
 		default:
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
@@ -189,9 +406,86 @@ func (app *application) updateTargetNotes(c *gin.Context) {
 		}
 	}
 
-	c.JSON(http.StatusOK, gin.H{"info": "success"})
+	c.JSON(http.StatusCreated, newNote)
 
-	slog.Info("Target notes updated", "id", target.ID)
+	slog.Info("Target note added", "target ID", id, "note ID", newNote.ID, "author", newNote.Author)
+}
+
+// @Summary List target notes
+// @Description Get the notes journal of a target, oldest first
+// @Tags missions
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Target ID"
+// @Success 200 {array} models.Note
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /missions/target_notes/{id} [get]
+func (app *application) getTargetNotes(c *gin.Context) {
+	idStr := c.Param("id")
+
+	id, err := strconv.Atoi(idStr)
+	if err != nil || id == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid target ID"})
+		return
+	}
+
+	notes, err := app.missions.Notes(c, id)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("Target with ID %d doesn't exist", id)})
+			return
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, notes)
+
+	slog.Info("Target notes returned", "target ID", id, "notes", len(notes))
+}
+
+// checkDueAt checks an optional due date is in the future, storing it in UTC.
+func checkDueAt(v *validator.Validator, dueAt **time.Time) {
+	if *dueAt == nil {
+		return
+	}
+	utc := (*dueAt).UTC()
+	*dueAt = &utc
+	v.Check(utc.After(time.Now()), "due_at", "must be in the future")
+}
+
+// checkNote validates a notes journal entry, trimming its author and body.
+func checkNote(v *validator.Validator, note *models.Note) {
+	checkAuthor(v, &note.Author)
+	note.Body = strings.TrimSpace(note.Body)
+	v.Check(note.Body != "", "body", validator.ErrEmptyFIeld.Error())
+}
+
+// checkCountry validates a country name, alias or ISO 3166-1 code, replacing it
+// with its alpha-2 code.
+func checkCountry(v *validator.Validator, country *string) {
+	if *country == "" {
+		v.AddError("country", validator.ErrEmptyFIeld.Error())
+		return
+	}
+
+	found, ok := countries.Lookup(*country)
+	if !ok {
+		v.AddError("country", fmt.Sprintf("unknown country %q, must be an ISO 3166 country name or code", *country))
+		return
+	}
+	*country = found.Alpha2
+}
+
+// checkAuthor validates the author of notes, trimming it.
+func checkAuthor(v *validator.Validator, author *string) {
+	*author = strings.TrimSpace(*author)
+	v.Check(*author != "", "author", validator.ErrEmptyFIeld.Error())
+	v.Check(len(*author) <= 100, "author", "must not be more than 100 bytes long")
 }
 
 // @Summary Delete a target
@@ -243,6 +537,60 @@ func (app *application) deleteTarget(c *gin.Context) {
 	slog.Info("Target deleted", "id", id)
 }
 
+// @Summary Complete a target
+// @Description Mark a target as completed. An assigned mission moves to in_progress, and is completed once all of its targets are done
+// @Tags missions
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Target ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /missions/complete_target/{id} [put]
+func (app *application) completeTarget(c *gin.Context) {
+	idStr := c.Param("id")
+
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid target ID"})
+		return
+	}
+
+	v := validator.New()
+	v.Check(id != 0, "id", validator.ErrZeroID.Error())
+	if !v.Valid() {
+		writeJSONValidationErrors(c, v.Errors)
+		return
+	}
+
+	missionCompleted, err := app.missions.CompleteTarget(c, id)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("Target with ID %d doesn't exist", id)})
+			return
+		case errors.Is(err, missions.ErrTargetCompleted):
+			c.JSON(http.StatusConflict, gin.H{"error": "Target is already completed"})
+			return
+		case errors.Is(err, missions.ErrMIssionCompleted):
+			c.JSON(http.StatusConflict, gin.H{"error": "Cannot complete target, mission is already completed"})
+			return
+		case errors.Is(err, missions.ErrMissionAborted):
+			c.JSON(http.StatusConflict, gin.H{"error": "Cannot complete target, mission is aborted"})
+			return
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"info": "success", "mission_completed": missionCompleted})
+
+	slog.Info("Target marked as completed", "id", id, "mission completed", missionCompleted)
+}
+
 // @Summary Add targets to a mission
 // @Description Add new targets to an existing mission
 // @Tags missions
@@ -252,6 +600,8 @@ func (app *application) deleteTarget(c *gin.Context) {
 // @Success 200 {object} map[string]interface{}
 // @Failure 400 {object} map[string]interface{}
 // @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 422 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
 // @Router /missions/add_targets [put]
 func (app *application) addTargets(c *gin.Context) {
@@ -264,10 +614,14 @@ func (app *application) addTargets(c *gin.Context) {
 
 	v := validator.New()
 	v.Check(mission.ID != 0, "id", validator.ErrZeroID.Error())
-	for _, target := range mission.Targets {
+	for i, target := range mission.Targets {
 		v.Check(target.ID != 0, "id", validator.ErrZeroID.Error())
-		v.Check(target.Country != "", "country", validator.ErrEmptyFIeld.Error())
+		checkCountry(v, &mission.Targets[i].Country)
 		v.Check(target.Name != "", "name", validator.ErrEmptyFIeld.Error())
+		for j := range target.Notes {
+			checkNote(v, &target.Notes[j])
+		}
+		checkDueAt(v, &mission.Targets[i].DueAt)
 	}
 
 	if !v.Valid() {
@@ -281,8 +635,11 @@ func (app *application) addTargets(c *gin.Context) {
 		case errors.Is(err, missions.ErrMIssionCompleted):
 			c.JSON(http.StatusNotFound, gin.H{"error": "Unable to add targets, mission is already completed"})
 			return
+		case errors.Is(err, missions.ErrMissionAborted):
+			c.JSON(http.StatusConflict, gin.H{"error": "Unable to add targets, mission is aborted"})
+			return
 		case errors.Is(err, missions.ErrTooManyTargets):
-			c.JSON(http.StatusNotFound, gin.H{"error": "Unable to add targets, too many targets"})
+			writeJSONValidationErrors(c, map[string]string{"targets": err.Error()})
 			return
 		case errors.Is(err, sql.ErrNoRows):
 			c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("Mission with ID %d doesn't exist", mission.ID)})
@@ -307,6 +664,7 @@ func (app *application) addTargets(c *gin.Context) {
 // @Success 200 {object} map[string]interface{}
 // @Failure 400 {object} map[string]interface{}
 // @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
 // @Router /missions/assign [put]
 func (app *application) assignCat(c *gin.Context) {
@@ -334,6 +692,10 @@ func (app *application) assignCat(c *gin.Context) {
 		case errors.Is(err, missions.ErrCatNotFound):
 			c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("Cat with id %d doesn't exist", mission.CatID)})
 			return
+		case errors.Is(err, missions.ErrInvalidTransition), errors.Is(err, missions.ErrCatUnavailable),
+			errors.Is(err, missions.ErrCatBusy):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
 		default:
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
 			return
@@ -345,42 +707,126 @@ func (app *application) assignCat(c *gin.Context) {
 	slog.Info("Cat assigned to a mission", "cat id", mission.CatID, "mission id", mission.ID)
 }
 
-// @Summary List all missions
-// @Description Get a list of all missions
+// @Summary List missions
+// @Description Get a page of missions with their targets, filtered and sorted by creation date
 // @Tags missions
 // @Accept  json
 // @Produce  json
-// @Success 200 {array} models.Mission
-// @Failure 404 {object} map[string]interface{}
+// @Param cat_id query int false "Assigned cat ID"
+// @Param status query string false "Comma separated statuses"
+// @Param created_from query string false "Created at or after (RFC 3339 or YYYY-MM-DD)"
+// @Param created_to query string false "Created at or before (RFC 3339 or YYYY-MM-DD)"
+// @Param due_from query string false "Due at or after (RFC 3339 or YYYY-MM-DD)"
+// @Param due_to query string false "Due at or before (RFC 3339 or YYYY-MM-DD)"
+// @Param overdue query bool false "Only unfinished missions past their due date or with an overdue target"
+// @Param country query string false "Target country, as a name or ISO 3166 code"
+// @Param sort query string false "created_at, or -created_at for newest first"
+// @Param cursor query string false "Cursor of the next page"
+// @Param limit query int false "Page size"
+// @Param include query string false "Comma separated relations to embed (cat)"
+// @Success 200 {object} missions.Page
+// @Failure 422 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
 // @Router /missions/list [get]
 func (app *application) listMissions(c *gin.Context) {
-	missions, err := app.missions.List(c)
-	if err != nil {
-		switch {
-		case errors.Is(err, sql.ErrNoRows):
-			c.JSON(http.StatusNotFound, gin.H{"error": "No missions found"})
-			return
-		default:
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
-			return
+	v := validator.New()
+	filter := missionsFilter(c, v)
+
+	app.writeMissionsPage(c, v, filter)
+}
+
+// @Summary List overdue missions
+// @Description Get a page of unfinished missions past their due date, or with an open target past its own
+// @Tags missions
+// @Accept  json
+// @Produce  json
+// @Param cat_id query int false "Assigned cat ID"
+// @Param status query string false "Comma separated statuses"
+// @Param created_from query string false "Created at or after (RFC 3339 or YYYY-MM-DD)"
+// @Param created_to query string false "Created at or before (RFC 3339 or YYYY-MM-DD)"
+// @Param due_from query string false "Due at or after (RFC 3339 or YYYY-MM-DD)"
+// @Param due_to query string false "Due at or before (RFC 3339 or YYYY-MM-DD)"
+// @Param country query string false "Target country, as a name or ISO 3166 code"
+// @Param sort query string false "created_at, or -created_at for newest first"
+// @Param cursor query string false "Cursor of the next page"
+// @Param limit query int false "Page size"
+// @Param include query string false "Comma separated relations to embed (cat)"
+// @Success 200 {object} missions.Page
+// @Failure 422 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /missions/overdue [get]
+func (app *application) listOverdueMissions(c *gin.Context) {
+	v := validator.New()
+	filter := missionsFilter(c, v)
+	filter.Overdue = true
+
+	app.writeMissionsPage(c, v, filter)
+}
+
+// missionsFilter reads the missions filter from the query, recording malformed parameters in v.
+func missionsFilter(c *gin.Context, v *validator.Validator) missions.Filter {
+	filter := missions.Filter{
+		CreatedFrom: queryTime(c, v, "created_from"),
+		CreatedTo:   queryTime(c, v, "created_to"),
+		DueFrom:     queryTime(c, v, "due_from"),
+		DueTo:       queryTime(c, v, "due_to"),
+		Overdue:     queryBool(c, v, "overdue"),
+		Country:     strings.TrimSpace(c.Query("country")),
+		Sort:        c.Query("sort"),
+		Cursor:      c.Query("cursor"),
+	}
+	if catID := queryInt(c, v, "cat_id"); catID != nil {
+		filter.CatID = *catID
+	}
+	if limit := queryInt(c, v, "limit"); limit != nil {
+		filter.Limit = *limit
+	}
+	for _, status := range strings.Split(c.Query("status"), ",") {
+		if status = strings.TrimSpace(status); status != "" {
+			filter.Statuses = append(filter.Statuses, models.MissionStatus(status))
 		}
 	}
 
-	c.JSON(http.StatusOK, missions)
+	if filter.Country != "" {
+		checkCountry(v, &filter.Country)
+	}
 
-	slog.Info("Missions returned", "missions", len(*missions))
+	include, err := missions.ParseInclude(c.Query("include"))
+	v.Check(err == nil, "include", fmt.Sprint(err))
+	filter.Include = include
+
+	return filter
+}
+
+func (app *application) writeMissionsPage(c *gin.Context, v *validator.Validator, filter missions.Filter) {
+	filter.Validate(v)
+	if !v.Valid() {
+		writeJSONValidationErrors(c, v.Errors)
+		return
+	}
+
+	page, err := app.missions.List(c, filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+
+	slog.Info("Missions returned", "missions", len(page.Missions), "total", page.Total)
 }
 
 // @Summary Get a mission by ID
-// @Description Get a mission by ID
+// @Description Get a mission by ID with its targets
 // @Tags missions
 // @Accept  json
 // @Produce  json
 // @Param id path int true "Mission ID"
+// @Param include query string false "Comma separated relations to embed (cat)"
 // @Success 200 {object} models.Mission
 // @Failure 400 {object} map[string]interface{}
 // @Failure 404 {object} map[string]interface{}
+// @Failure 422 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
 // @Router /missions/get/{id} [get]
 func (app *application) getMission(c *gin.Context) {
@@ -392,14 +838,17 @@ func (app *application) getMission(c *gin.Context) {
 		return
 	}
 
+	include, err := missions.ParseInclude(c.Query("include"))
+
 	v := validator.New()
 	v.Check(id != 0, "id", validator.ErrZeroID.Error())
+	v.Check(err == nil, "include", fmt.Sprint(err))
 	if !v.Valid() {
 		writeJSONValidationErrors(c, v.Errors)
 		return
 	}
 
-	mission, err := app.missions.Get(c, id)
+	mission, err := app.missions.Get(c, id, include)
 	if err != nil {
 		switch {
 		case errors.Is(err, sql.ErrNoRows):
@@ -415,3 +864,201 @@ func (app *application) getMission(c *gin.Context) {
 
 	slog.Info("Mission returned", "mission ID", id)
 }
+
+// @Summary Get a mission's assignment history
+// @Description Get every cat assigned to a mission over time, oldest first
+// @Tags missions
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Mission ID"
+// @Success 200 {array} models.Assignment
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /missions/assignments/{id} [get]
+func (app *application) getMissionAssignments(c *gin.Context) {
+	idStr := c.Param("id")
+
+	id, err := strconv.Atoi(idStr)
+	if err != nil || id == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid mission ID"})
+		return
+	}
+
+	assignments, err := app.missions.Assignments(c, id)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("Mission with ID %d doesn't exist", id)})
+			return
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, assignments)
+
+	slog.Info("Mission assignments returned", "mission ID", id, "assignments", len(assignments))
+}
+
+// dueDateRequest sets a due date, or clears it when due_at is null.
+type dueDateRequest struct {
+	DueAt *time.Time `json:"due_at"`
+}
+
+// @Summary Set a mission's due date
+// @Description Set the due date of a mission that hasn't ended, or clear it with a null due_at. The mission is checked for being overdue against the new date
+// @Tags missions
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Mission ID"
+// @Param due body dueDateRequest true "Due date in the future, or null"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 422 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /missions/due_date/{id} [put]
+func (app *application) setMissionDueDate(c *gin.Context) {
+	idStr := c.Param("id")
+
+	id, err := strconv.Atoi(idStr)
+	if err != nil || id == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid mission ID"})
+		return
+	}
+
+	var req dueDateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	v := validator.New()
+	checkDueAt(v, &req.DueAt)
+	if !v.Valid() {
+		writeJSONValidationErrors(c, v.Errors)
+		return
+	}
+
+	if err := app.missions.SetDueAt(c, id, req.DueAt); err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("Mission with ID %d doesn't exist", id)})
+			return
+		case errors.Is(err, missions.ErrMIssionCompleted):
+			c.JSON(http.StatusConflict, gin.H{"error": "Cannot set due date, mission is already completed"})
+			return
+		case errors.Is(err, missions.ErrMissionAborted):
+			c.JSON(http.StatusConflict, gin.H{"error": "Cannot set due date, mission is aborted"})
+			return
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"info": "success"})
+
+	slog.Info("Mission due date set", "id", id, "due at", req.DueAt)
+}
+
+// @Summary Set a target's due date
+// @Description Set the due date of an open target, or clear it with a null due_at. The target is checked for being overdue against the new date
+// @Tags missions
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Target ID"
+// @Param due body dueDateRequest true "Due date in the future, or null"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 422 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /missions/target_due_date/{id} [put]
+func (app *application) setTargetDueDate(c *gin.Context) {
+	idStr := c.Param("id")
+
+	id, err := strconv.Atoi(idStr)
+	if err != nil || id == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid target ID"})
+		return
+	}
+
+	var req dueDateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	v := validator.New()
+	checkDueAt(v, &req.DueAt)
+	if !v.Valid() {
+		writeJSONValidationErrors(c, v.Errors)
+		return
+	}
+
+	if err := app.missions.SetTargetDueAt(c, id, req.DueAt); err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("Target with ID %d doesn't exist", id)})
+			return
+		case errors.Is(err, missions.ErrMIssionCompleted):
+			c.JSON(http.StatusConflict, gin.H{"error": "Cannot set due date, mission is already completed"})
+			return
+		case errors.Is(err, missions.ErrMissionAborted):
+			c.JSON(http.StatusConflict, gin.H{"error": "Cannot set due date, mission is aborted"})
+			return
+		case errors.Is(err, missions.ErrTargetCompleted):
+			c.JSON(http.StatusConflict, gin.H{"error": "Cannot set due date, target is already completed"})
+			return
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"info": "success"})
+
+	slog.Info("Target due date set", "id", id, "due at", req.DueAt)
+}
+
+// @Summary Get a mission's missed deadlines
+// @Description Get the overdue events of a mission and its targets, oldest first. Events with a target_id are missed target deadlines
+// @Tags missions
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Mission ID"
+// @Success 200 {array} models.OverdueEvent
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /missions/overdue_events/{id} [get]
+func (app *application) getOverdueEvents(c *gin.Context) {
+	idStr := c.Param("id")
+
+	id, err := strconv.Atoi(idStr)
+	if err != nil || id == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid mission ID"})
+		return
+	}
+
+	events, err := app.missions.OverdueEvents(c, id)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("Mission with ID %d doesn't exist", id)})
+			return
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, events)
+
+	slog.Info("Mission overdue events returned", "mission ID", id, "events", len(events))
+}
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"spy-cat-agency/internal/payroll"
+	"spy-cat-agency/internal/validator"
+
+	"github.com/gin-gonic/gin"
+)
+
+type payrollRunRequest struct {
+	Period string `json:"period"`
+}
+
+// @Summary Run payroll
+// @Description Compute payroll for a past or the current month. Running an unchanged month again returns the existing run, otherwise a new revision is stored with a diff against the previous one
+// @Tags payroll
+// @Accept  json
+// @Produce  json
+// @Param run body payrollRunRequest true "Period formatted as YYYY-MM"
+// @Success 200 {object} payroll.RunResult
+// @Success 201 {object} payroll.RunResult
+// @Failure 400 {object} map[string]interface{}
+// @Failure 422 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /payroll/run [post]
+func (app *application) runPayroll(c *gin.Context) {
+	var req payrollRunRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	period, err := payroll.ParsePeriod(req.Period)
+
+	v := validator.New()
+	v.Check(err == nil, "period", payroll.ErrInvalidPeriod.Error())
+	v.Check(!period.Future(time.Now()), "period", payroll.ErrFuturePeriod.Error())
+	if !v.Valid() {
+		writeJSONValidationErrors(c, v.Errors)
+		return
+	}
+
+	result, err := app.payroll.Run(c, period)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	status := http.StatusOK
+	if result.Created {
+		status = http.StatusCreated
+	}
+	c.JSON(status, result)
+
+	slog.Info("Payroll run", "period", period, "revision", result.Run.Revision, "created", result.Created, "changes", len(result.Diff))
+}
+
+// @Summary List payroll runs
+// @Description Get payroll runs without their lines, newest first
+// @Tags payroll
+// @Accept  json
+// @Produce  json
+// @Param period query string false "Period formatted as YYYY-MM"
+// @Success 200 {array} models.PayrollRun
+// @Failure 422 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /payroll/runs [get]
+func (app *application) listPayrollRuns(c *gin.Context) {
+	period := c.Query("period")
+
+	v := validator.New()
+	if period != "" {
+		_, err := payroll.ParsePeriod(period)
+		v.Check(err == nil, "period", payroll.ErrInvalidPeriod.Error())
+	}
+	if !v.Valid() {
+		writeJSONValidationErrors(c, v.Errors)
+		return
+	}
+
+	runs, err := app.payroll.List(c, period)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, runs)
+
+	slog.Info("Payroll runs listed", "runs", len(runs))
+}
+
+// @Summary Get a payroll run
+// @Description Get a payroll run with its lines
+// @Tags payroll
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Payroll run ID"
+// @Success 200 {object} models.PayrollRun
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /payroll/runs/{id} [get]
+func (app *application) getPayrollRun(c *gin.Context) {
+	idStr := c.Param("id")
+
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid payroll run ID"})
+		return
+	}
+
+	run, err := app.payroll.Get(c, id)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("Payroll run with ID %d doesn't exist", id)})
+			return
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, run)
+
+	slog.Info("Payroll run returned", "id", id)
+}
+
+// @Summary Export a payroll run
+// @Description Download a payroll run as CSV or JSON
+// @Tags payroll
+// @Produce  text/csv
+// @Produce  json
+// @Param id path int true "Payroll run ID"
+// @Param format query string false "csv (default) or json"
+// @Success 200 {file} file
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 422 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /payroll/runs/{id}/export [get]
+func (app *application) exportPayrollRun(c *gin.Context) {
+	idStr := c.Param("id")
+
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid payroll run ID"})
+		return
+	}
+
+	format := c.DefaultQuery("format", "csv")
+
+	v := validator.New()
+	v.Check(format == "csv" || format == "json", "format", "must be csv or json")
+	if !v.Valid() {
+		writeJSONValidationErrors(c, v.Errors)
+		return
+	}
+
+	run, err := app.payroll.Get(c, id)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("Payroll run with ID %d doesn't exist", id)})
+			return
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+			return
+		}
+	}
+
+	filename := fmt.Sprintf("payroll-%s-r%d.%s", run.Period, run.Revision, format)
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+
+	switch format {
+	case "json":
+		c.JSON(http.StatusOK, run)
+	default:
+		c.Header("Content-Type", "text/csv")
+		c.Status(http.StatusOK)
+		if err := payroll.WriteCSV(c.Writer, run); err != nil {
+			slog.Error("Payroll export", "write csv", err)
+			return
+		}
+	}
+
+	slog.Info("Payroll run exported", "id", id, "format", format)
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"spy-cat-agency/internal/missions"
+	"spy-cat-agency/internal/models"
+	"spy-cat-agency/internal/paging"
+	"spy-cat-agency/internal/validator"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Recommend cats for a mission
+// @Description Rank the available cats for a draft or assigned mission by experience, past success in the mission's countries, their breed's success there and salary, explaining every score
+// @Tags missions
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Mission ID"
+// @Param budget query number false "Most salary to pay, cats paid more or in another currency are left out"
+// @Param currency query string false "Currency of the budget, USD by default"
+// @Param limit query int false "Number of candidates, 20 by default"
+// @Success 200 {object} missions.Recommendation
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 422 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /missions/recommend/{id} [get]
+func (app *application) recommendCats(c *gin.Context) {
+	idStr := c.Param("id")
+
+	id, err := strconv.Atoi(idStr)
+	if err != nil || id == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid mission ID"})
+		return
+	}
+
+	v := validator.New()
+	opts := missions.RecommendOptions{
+		Budget:   queryMoney(c, v, "budget"),
+		Currency: c.Query("currency"),
+	}
+	if limit := queryInt(c, v, "limit"); limit != nil {
+		opts.Limit = *limit
+	}
+	checkRecommendOptions(v, &opts)
+	if !v.Valid() {
+		writeJSONValidationErrors(c, v.Errors)
+		return
+	}
+
+	recommendation, err := app.missions.Recommend(c, id, opts)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("Mission with ID %d doesn't exist", id)})
+			return
+		case errors.Is(err, missions.ErrInvalidTransition):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, recommendation)
+
+	slog.Info("Cats recommended for a mission", "mission id", id, "candidates", len(recommendation.Candidates))
+}
+
+// @Summary Assign cats to many missions
+// @Description Assign available cats to draft missions with the one-to-one matching of the largest total recommendation score, in one transaction. Fails with 409 if a listed mission isn't a draft
+// @Tags missions
+// @Accept  json
+// @Produce  json
+// @Param options body missions.BulkOptions true "Draft missions to assign, the oldest ones if empty, an optional budget and dry run"
+// @Success 200 {object} missions.BulkResult
+// @Failure 400 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 422 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /missions/assign_bulk [post]
+func (app *application) assignBulk(c *gin.Context) {
+	var opts missions.BulkOptions
+
+	if err := c.ShouldBindJSON(&opts); err != nil {
+		writeJSONBindError(c, err, "budget")
+		return
+	}
+
+	v := validator.New()
+	v.Check(len(opts.MissionIDs) <= paging.MaxLimit, "mission_ids", fmt.Sprintf("can't have more than %d missions", paging.MaxLimit))
+	for _, id := range opts.MissionIDs {
+		v.Check(id != 0, "mission_ids", validator.ErrZeroID.Error())
+	}
+	checkRecommendOptions(v, &opts.RecommendOptions)
+	if !v.Valid() {
+		writeJSONValidationErrors(c, v.Errors)
+		return
+	}
+
+	result, err := app.missions.AssignBulk(c, opts)
+	if err != nil {
+		switch {
+		case errors.Is(err, missions.ErrNoDraftMissions), errors.Is(err, missions.ErrNotDrafts),
+			errors.Is(err, missions.ErrCatUnavailable), errors.Is(err, missions.ErrCatBusy):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, result)
+
+	slog.Info("Cats assigned to missions", "assigned", len(result.Assignments), "unmatched", len(result.Unmatched), "dry run", result.DryRun)
+}
+
+// checkRecommendOptions validates the budget, defaulting its currency to USD.
+func checkRecommendOptions(v *validator.Validator, opts *missions.RecommendOptions) {
+	if opts.Currency == "" {
+		opts.Currency = models.DefaultCurrency
+	}
+	opts.Currency = strings.ToUpper(opts.Currency)
+	v.Check(models.ValidCurrency(opts.Currency), "currency", "must be an ISO 4217 currency code")
+	if opts.Budget != nil {
+		v.Check(!opts.Budget.IsNegative(), "budget", "can't be negative")
+	}
+}
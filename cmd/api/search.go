@@ -0,0 +1,64 @@
+package main
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"spy-cat-agency/internal/missions"
+	"spy-cat-agency/internal/models"
+	"spy-cat-agency/internal/validator"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Search targets and notes
+// @Description Full-text search over target names, countries and notes, best matches first
+// @Tags search
+// @Accept  json
+// @Produce  json
+// @Param q query string true "Search query, quoted phrases, or and -word are supported"
+// @Param cat_id query int false "Only search missions assigned to this cat"
+// @Param status query string false "Comma separated mission statuses (draft, assigned, in_progress, completed, aborted)"
+// @Param exclude_completed query bool false "Leave out completed targets and their notes"
+// @Param cursor query string false "Cursor from the previous page"
+// @Param limit query int false "Page size, 20 by default"
+// @Success 200 {object} missions.SearchPage
+// @Failure 422 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /search [get]
+func (app *application) search(c *gin.Context) {
+	v := validator.New()
+	query := missions.SearchQuery{
+		Text:             c.Query("q"),
+		ExcludeCompleted: queryBool(c, v, "exclude_completed"),
+		Cursor:           c.Query("cursor"),
+	}
+	if catID := queryInt(c, v, "cat_id"); catID != nil {
+		query.CatID = *catID
+	}
+	if limit := queryInt(c, v, "limit"); limit != nil {
+		query.Limit = *limit
+	}
+	for _, status := range strings.Split(c.Query("status"), ",") {
+		if status = strings.TrimSpace(status); status != "" {
+			query.Statuses = append(query.Statuses, models.MissionStatus(status))
+		}
+	}
+
+	query.Validate(v)
+	if !v.Valid() {
+		writeJSONValidationErrors(c, v.Errors)
+		return
+	}
+
+	page, err := app.missions.Search(c, query)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+
+	slog.Info("Search results returned", "query", query.Text, "hits", len(page.Hits), "total", page.Total)
+}
@@ -20,25 +20,80 @@ func (app *application) routes() *gin.Engine {
 	{
 		cats.POST("/create", app.createCat)
 		cats.DELETE("/remove/:id", app.removeCat)
+		cats.PUT("/restore/:id", app.restoreCat)
+		cats.PATCH("/update/:id", app.updateCat)
 		cats.PUT("/update_salary", app.updateCatsSalary)
+		cats.GET("/salary_history/:id", app.getSalaryHistory)
+		cats.GET("/salary_changes", app.listSalaryChanges)
 		cats.GET("/list", app.listCats)
+		cats.GET("/available", app.listAvailableCats)
 		cats.GET("/get/:id", app.getCat)
 	}
 
+	// Breeds
+	breeds := r.Group("/breeds")
+	{
+		breeds.GET("", app.listBreeds)
+		breeds.GET("/:id", app.getBreed)
+	}
+
 	// Missions
 	missions := r.Group("/missions")
 	{
 		missions.POST("/create", app.createMission)
+		missions.POST("/from_template", app.createMissionFromTemplate)
+		missions.POST("/clone/:id", app.cloneMission)
 		missions.DELETE("/delete/:id", app.deleteMission)
+		missions.PUT("/start/:id", app.startMission)
 		missions.PUT("/complete/:id", app.completeMission)
-		missions.PUT("/update_notes", app.updateTargetNotes)
+		missions.PUT("/abort/:id", app.abortMission)
+		missions.POST("/add_note/:id", app.addTargetNote)
+		missions.GET("/target_notes/:id", app.getTargetNotes)
 		missions.DELETE("/delete_target/:id", app.deleteTarget)
+		missions.PUT("/complete_target/:id", app.completeTarget)
 		missions.PUT("/add_targets", app.addTargets)
 		missions.PUT("/assign", app.assignCat)
+		missions.POST("/assign_bulk", app.assignBulk)
+		missions.GET("/recommend/:id", app.recommendCats)
+		missions.PUT("/unassign/:id", app.unassignCat)
+		missions.GET("/assignments/:id", app.getMissionAssignments)
 		missions.GET("/list", app.listMissions)
+		missions.GET("/overdue", app.listOverdueMissions)
+		missions.GET("/overdue_events/:id", app.getOverdueEvents)
+		missions.PUT("/due_date/:id", app.setMissionDueDate)
+		missions.PUT("/target_due_date/:id", app.setTargetDueDate)
 		missions.GET("/get/:id", app.getMission)
 	}
 
+	// Mission templates
+	templates := r.Group("/templates")
+	{
+		templates.POST("/create", app.createTemplate)
+		templates.PUT("/update/:id", app.updateTemplate)
+		templates.DELETE("/delete/:id", app.deleteTemplate)
+		templates.GET("/list", app.listTemplates)
+		templates.GET("/versions/:id", app.getTemplateVersions)
+		templates.GET("/get/:id", app.getTemplate)
+	}
+
+	// Search
+	r.GET("/search", app.search)
+
+	// Payroll
+	payroll := r.Group("/payroll")
+	{
+		payroll.POST("/run", app.runPayroll)
+		payroll.GET("/runs", app.listPayrollRuns)
+		payroll.GET("/runs/:id", app.getPayrollRun)
+		payroll.GET("/runs/:id/export", app.exportPayrollRun)
+	}
+
+	// Admin
+	admin := r.Group("/admin")
+	{
+		admin.GET("/policy", app.getPolicy)
+	}
+
 	r.GET("/healthcheck", app.healthcheck)
 
 	return r
@@ -0,0 +1,262 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"spy-cat-agency/internal/missions"
+	"spy-cat-agency/internal/models"
+	"spy-cat-agency/internal/validator"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Create a mission template
+// @Description Create the first version of a named list of targets with default notes
+// @Tags templates
+// @Accept  json
+// @Produce  json
+// @Param template body models.MissionTemplate true "Template object"
+// @Success 201 {object} models.MissionTemplate
+// @Failure 400 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 422 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /templates/create [post]
+func (app *application) createTemplate(c *gin.Context) {
+	var template models.MissionTemplate
+
+	if err := c.ShouldBindJSON(&template); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid template body"})
+		return
+	}
+
+	v := validator.New()
+	template.Name = strings.TrimSpace(template.Name)
+	v.Check(template.Name != "", "name", validator.ErrEmptyFIeld.Error())
+	v.Check(len(template.Name) <= 100, "name", "must not be more than 100 bytes long")
+	checkTemplateTargets(v, template.Targets)
+	if !v.Valid() {
+		writeJSONValidationErrors(c, v.Errors)
+		return
+	}
+
+	newTemplate, err := app.missions.CreateTemplate(c, &template)
+	if err != nil {
+		switch {
+		case errors.Is(err, missions.ErrTemplateExists):
+			c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("Template %q already exists, update it to add a version", template.Name)})
+			return
+		case errors.Is(err, missions.ErrTooManyTargets):
+			writeJSONValidationErrors(c, map[string]string{"targets": err.Error()})
+			return
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+			return
+		}
+	}
+
+	c.JSON(http.StatusCreated, newTemplate)
+
+	slog.Info("Template created", "id", newTemplate.ID, "name", newTemplate.Name)
+}
+
+// @Summary Update a mission template
+// @Description Add a new version of a template with the given targets, the template ID must be its latest version
+// @Tags templates
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Template ID"
+// @Param template body models.MissionTemplate true "Template object, its name is ignored"
+// @Success 201 {object} models.MissionTemplate
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 422 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /templates/update/{id} [put]
+func (app *application) updateTemplate(c *gin.Context) {
+	idStr := c.Param("id")
+
+	id, err := strconv.Atoi(idStr)
+	if err != nil || id == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid template ID"})
+		return
+	}
+
+	var template models.MissionTemplate
+
+	if err := c.ShouldBindJSON(&template); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid template body"})
+		return
+	}
+
+	v := validator.New()
+	checkTemplateTargets(v, template.Targets)
+	if !v.Valid() {
+		writeJSONValidationErrors(c, v.Errors)
+		return
+	}
+
+	newTemplate, err := app.missions.UpdateTemplate(c, id, template.Targets)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("Template with ID %d doesn't exist", id)})
+			return
+		case errors.Is(err, missions.ErrTemplateOutdated):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		case errors.Is(err, missions.ErrTooManyTargets):
+			writeJSONValidationErrors(c, map[string]string{"targets": err.Error()})
+			return
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+			return
+		}
+	}
+
+	c.JSON(http.StatusCreated, newTemplate)
+
+	slog.Info("Template updated", "id", newTemplate.ID, "name", newTemplate.Name, "version", newTemplate.Version)
+}
+
+// @Summary Delete a mission template
+// @Description Delete all the versions of a template, missions created from it are kept
+// @Tags templates
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Template ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /templates/delete/{id} [delete]
+func (app *application) deleteTemplate(c *gin.Context) {
+	idStr := c.Param("id")
+
+	id, err := strconv.Atoi(idStr)
+	if err != nil || id == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid template ID"})
+		return
+	}
+
+	if err := app.missions.DeleteTemplate(c, id); err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("Template with ID %d doesn't exist", id)})
+			return
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"info": "success"})
+
+	slog.Info("Template deleted", "id", id)
+}
+
+// @Summary List mission templates
+// @Description Get the latest version of every template
+// @Tags templates
+// @Accept  json
+// @Produce  json
+// @Success 200 {array} models.MissionTemplate
+// @Failure 500 {object} map[string]interface{}
+// @Router /templates/list [get]
+func (app *application) listTemplates(c *gin.Context) {
+	templates, err := app.missions.ListTemplates(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, templates)
+
+	slog.Info("Templates returned", "templates", len(templates))
+}
+
+// @Summary Get a mission template by ID
+// @Description Get a template version with its targets
+// @Tags templates
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Template ID"
+// @Success 200 {object} models.MissionTemplate
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /templates/get/{id} [get]
+func (app *application) getTemplate(c *gin.Context) {
+	app.writeTemplate(c, func(id int) (any, error) {
+		return app.missions.GetTemplate(c, id)
+	})
+}
+
+// @Summary List the versions of a mission template
+// @Description Get all the versions of a template, oldest first
+// @Tags templates
+// @Accept  json
+// @Produce  json
+// @Param id path int true "ID of any version of the template"
+// @Success 200 {array} models.MissionTemplate
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /templates/versions/{id} [get]
+func (app *application) getTemplateVersions(c *gin.Context) {
+	app.writeTemplate(c, func(id int) (any, error) {
+		return app.missions.TemplateVersions(c, id)
+	})
+}
+
+// writeTemplate writes what get returns for the template ID in the path.
+func (app *application) writeTemplate(c *gin.Context, get func(id int) (any, error)) {
+	idStr := c.Param("id")
+
+	id, err := strconv.Atoi(idStr)
+	if err != nil || id == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid template ID"})
+		return
+	}
+
+	template, err := get(id)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("Template with ID %d doesn't exist", id)})
+			return
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, template)
+
+	slog.Info("Template returned", "id", id)
+}
+
+// checkTemplateTargets validates the targets of a template, trimming their notes.
+func checkTemplateTargets(v *validator.Validator, targets []models.TemplateTarget) {
+	v.Check(len(targets) > 0, "targets", validator.ErrEmptyFIeld.Error())
+
+	names := make(map[string]bool, len(targets))
+	for i := range targets {
+		t := &targets[i]
+		v.Check(t.Name != "", "name", validator.ErrEmptyFIeld.Error())
+		checkCountry(v, &t.Country)
+		v.Check(!names[t.Name], "name", fmt.Sprintf("target %q is listed twice", t.Name))
+		names[t.Name] = true
+		for j := range t.Notes {
+			t.Notes[j] = strings.TrimSpace(t.Notes[j])
+			v.Check(t.Notes[j] != "", "notes", "can't contain empty notes")
+		}
+	}
+}
@@ -0,0 +1,371 @@
+package cats
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	defaultRefreshInterval = time.Hour
+	retryBaseDelay         = time.Second
+	retryMaxDelay          = time.Minute
+)
+
+type Weight struct {
+	Imperial string `json:"imperial" yaml:"imperial"`
+	Metric   string `json:"metric" yaml:"metric"`
+}
+
+type Breed struct {
+	ID          string `json:"id" yaml:"id"`
+	Name        string `json:"name" yaml:"name"`
+	Temperament string `json:"temperament,omitempty" yaml:"temperament"`
+	Origin      string `json:"origin,omitempty" yaml:"origin"`
+	LifeSpan    string `json:"life_span,omitempty" yaml:"life_span"`
+	Weight      Weight `json:"weight" yaml:"weight"`
+}
+
+type BreedsConfig struct {
+	// Provider is one of ProviderAPI, ProviderFile or ProviderDB.
+	Provider string
+	Api      string
+	File     string
+	// RefreshInterval is how often the cache is refreshed, and how old it
+	// may get before a lookup triggers a refresh in the background.
+	RefreshInterval time.Duration
+	// SnapshotPath is where the last good breed list is persisted.
+	// Snapshots are disabled if empty.
+	SnapshotPath string
+	MaxRetries   int
+}
+
+type Breeds struct {
+	Provider BreedProvider
+	// Cache holds the breeds by normalized name, ids maps normalized
+	// breed IDs to normalized names.
+	Cache map[string]Breed
+	ids   map[string]string
+	mu    sync.RWMutex
+
+	refreshInterval time.Duration
+	snapshotPath    string
+	maxRetries      int
+	checkedAt       time.Time
+	refreshing      atomic.Bool
+}
+
+func NewBreeds(provider BreedProvider, cfg BreedsConfig) *Breeds {
+	if cfg.RefreshInterval <= 0 {
+		cfg.RefreshInterval = defaultRefreshInterval
+	}
+
+	return &Breeds{
+		Provider:        provider,
+		Cache:           map[string]Breed{},
+		ids:             map[string]string{},
+		mu:              sync.RWMutex{},
+		refreshInterval: cfg.RefreshInterval,
+		snapshotPath:    cfg.SnapshotPath,
+		maxRetries:      cfg.MaxRetries,
+	}
+}
+
+// Load populates the cache at startup. If the breeds provider is unavailable
+// the last snapshot is loaded instead, the background refresher catches up later.
+func (b *Breeds) Load(ctx context.Context) error {
+	err := b.Fetch(ctx)
+	if err == nil {
+		return nil
+	}
+	slog.Warn("Breeds provider unavailable, loading snapshot", "error", err, "snapshot", b.snapshotPath)
+
+	if snapErr := b.loadSnapshot(); snapErr != nil {
+		return errors.Join(err, snapErr)
+	}
+
+	return nil
+}
+
+// Run refreshes the cache every refresh interval until ctx is done.
+func (b *Breeds) Run(ctx context.Context) {
+	ticker := time.NewTicker(b.refreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			b.refresh(ctx)
+		}
+	}
+}
+
+// Exists reports whether the breed is known by name or ID.
+func (b *Breeds) Exists(breed string) bool {
+	_, found := b.Lookup(breed)
+	return found
+}
+
+// Lookup finds a breed by name or ID, ignoring case and extra spaces.
+// A stale cache is still used for the answer while a refresh runs in the background.
+func (b *Breeds) Lookup(breed string) (Breed, bool) {
+	defer b.revalidate()
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	key := normalizeBreed(breed)
+	if found, ok := b.Cache[key]; ok {
+		return found, true
+	}
+	found, ok := b.Cache[b.ids[key]]
+	return found, ok
+}
+
+// Suggest returns up to n breed names closest to breed by edit distance.
+func (b *Breeds) Suggest(breed string, n int) []string {
+	key := normalizeBreed(breed)
+	maxDistance := max(2, utf8.RuneCountInString(key)/2)
+
+	type match struct {
+		name     string
+		distance int
+	}
+
+	b.mu.RLock()
+	matches := make([]match, 0, len(b.Cache))
+	for name, found := range b.Cache {
+		if d := levenshtein(key, name); d <= maxDistance {
+			matches = append(matches, match{name: found.Name, distance: d})
+		}
+	}
+	b.mu.RUnlock()
+
+	slices.SortFunc(matches, func(a, b match) int {
+		if a.distance != b.distance {
+			return a.distance - b.distance
+		}
+		return strings.Compare(a.name, b.name)
+	})
+
+	names := make([]string, 0, n)
+	for _, m := range matches[:min(n, len(matches))] {
+		names = append(names, m.name)
+	}
+
+	return names
+}
+
+// Get finds a breed by ID.
+func (b *Breeds) Get(id string) (Breed, bool) {
+	defer b.revalidate()
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	name, ok := b.ids[normalizeBreed(id)]
+	if !ok {
+		return Breed{}, false
+	}
+	return b.Cache[name], true
+}
+
+// All returns the cached catalog sorted by name.
+func (b *Breeds) All() []Breed {
+	defer b.revalidate()
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	breeds := make([]Breed, 0, len(b.Cache))
+	for _, breed := range b.Cache {
+		breeds = append(breeds, breed)
+	}
+	slices.SortFunc(breeds, func(a, b Breed) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return breeds
+}
+
+// revalidate starts a background refresh if the cache is stale.
+func (b *Breeds) revalidate() {
+	b.mu.RLock()
+	stale := time.Since(b.checkedAt) > b.refreshInterval
+	b.mu.RUnlock()
+
+	if stale {
+		go b.refresh(context.Background())
+	}
+}
+
+// refresh fetches the breeds with jittered exponential backoff.
+// Only one refresh runs at a time, concurrent calls return right away.
+func (b *Breeds) refresh(ctx context.Context) {
+	if !b.refreshing.CompareAndSwap(false, true) {
+		return
+	}
+	defer b.refreshing.Store(false)
+
+	delay := retryBaseDelay
+	for attempt := 0; ; attempt++ {
+		err := b.Fetch(ctx)
+		if err == nil {
+			return
+		}
+
+		if attempt >= b.maxRetries {
+			slog.Error("Breeds refresh failed, serving stale cache", "attempts", attempt+1, "error", err)
+			b.mu.Lock()
+			b.checkedAt = time.Now()
+			b.mu.Unlock()
+			return
+		}
+
+		// Sleep a random duration between half and the whole delay
+		sleep := delay/2 + rand.N(delay/2+1)
+		slog.Warn("Breeds refresh failed, retrying", "attempt", attempt+1, "in", sleep, "error", err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(sleep):
+		}
+
+		delay = min(delay*2, retryMaxDelay)
+	}
+}
+
+func (b *Breeds) Fetch(ctx context.Context) error {
+	breeds, err := b.Provider.Breeds(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(breeds) == 0 {
+		return errors.New("Breeds provider returned no breeds")
+	}
+
+	b.store(breeds)
+
+	if err := b.saveSnapshot(breeds); err != nil {
+		slog.Error("Saving breeds snapshot", "error", err)
+	}
+
+	return nil
+}
+
+func (b *Breeds) store(breeds []Breed) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.Cache = make(map[string]Breed, len(breeds))
+	b.ids = make(map[string]string, len(breeds))
+	for _, breed := range breeds {
+		b.Cache[normalizeBreed(breed.Name)] = breed
+		b.ids[normalizeBreed(breed.ID)] = normalizeBreed(breed.Name)
+	}
+	b.checkedAt = time.Now()
+
+	slog.Info("Breeds cache populated", "breeds", len(b.Cache))
+}
+
+func (b *Breeds) loadSnapshot() error {
+	if b.snapshotPath == "" {
+		return errors.New("Breeds snapshot is disabled")
+	}
+
+	data, err := os.ReadFile(b.snapshotPath)
+	if err != nil {
+		return fmt.Errorf("Error reading breeds snapshot: %w", err)
+	}
+
+	var breeds []Breed
+	if err := json.Unmarshal(data, &breeds); err != nil {
+		return fmt.Errorf("Error unmarshaling breeds snapshot: %w", err)
+	}
+
+	b.store(breeds)
+
+	// Keep the snapshot stale so the first lookup tries the API again
+	b.mu.Lock()
+	b.checkedAt = time.Time{}
+	b.mu.Unlock()
+
+	return nil
+}
+
+// saveSnapshot atomically replaces the snapshot with the given breeds.
+func (b *Breeds) saveSnapshot(breeds []Breed) error {
+	if b.snapshotPath == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(breeds)
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(b.snapshotPath)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(dir, filepath.Base(b.snapshotPath)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), b.snapshotPath)
+}
+
+// normalizeBreed lowercases a breed name or ID and collapses its whitespace.
+func normalizeBreed(breed string) string {
+	return strings.ToLower(strings.Join(strings.Fields(breed), " "))
+}
+
+// levenshtein returns the edit distance between two strings.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(rb)]
+}
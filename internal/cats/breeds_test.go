@@ -0,0 +1,139 @@
+package cats
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func testBreeds() *Breeds {
+	b := NewBreeds(nil, BreedsConfig{})
+	b.store([]Breed{
+		{ID: "siam", Name: "Siamese"},
+		{ID: "sibe", Name: "Siberian"},
+		{ID: "sphy", Name: "Sphynx"},
+		{ID: "manx", Name: "Manx"},
+		{ID: "mcoo", Name: "Maine Coon"},
+		{ID: "pers", Name: "Persian"},
+		{ID: "bali", Name: "Balinese"},
+	})
+	return b
+}
+
+func TestLookup(t *testing.T) {
+	b := testBreeds()
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{in: "Siamese", want: "Siamese"},
+		{in: "siamese", want: "Siamese"},
+		{in: "  maine   COON ", want: "Maine Coon"},
+		{in: "SIAM", want: "Siamese"},
+		{in: "Siamse"},
+		{in: ""},
+	}
+
+	for _, tt := range tests {
+		got, ok := b.Lookup(tt.in)
+		if ok != (tt.want != "") || got.Name != tt.want {
+			t.Errorf("Lookup(%q) = %q, %v, want %q", tt.in, got.Name, ok, tt.want)
+		}
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	b := testBreeds()
+	tests := []struct {
+		in   string
+		n    int
+		want []string
+	}{
+		{in: "siamese", n: 3, want: []string{"Siamese"}},
+		{in: "Siamse", n: 3, want: []string{"Siamese"}},
+		{in: "  maine   COON ", n: 3, want: []string{"Maine Coon"}},
+		// Ties are ranked by name
+		{in: "Spanx", n: 3, want: []string{"Manx", "Sphynx"}},
+		{in: "Spanx", n: 1, want: []string{"Manx"}},
+		// Up to half the length of the input, and at least 2, edits away
+		{in: "Persn", n: 3, want: []string{"Persian"}},
+		{in: "Prsn", n: 3, want: []string{}},
+		{in: "Dog", n: 3, want: []string{}},
+	}
+
+	for _, tt := range tests {
+		if got := b.Suggest(tt.in, tt.n); !slices.Equal(got, tt.want) {
+			t.Errorf("Suggest(%q, %d) = %q, want %q", tt.in, tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "data")
+	cfg := BreedsConfig{SnapshotPath: filepath.Join(dir, "breeds.json")}
+
+	saved := NewBreeds(nil, cfg)
+	if err := saved.saveSnapshot([]Breed{{ID: "abys", Name: "Abyssinian"}}); err != nil {
+		t.Fatal(err)
+	}
+	// A newer snapshot replaces the previous one
+	if err := saved.saveSnapshot([]Breed{{ID: "siam", Name: "Siamese"}, {ID: "beng", Name: "Bengal"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Name() != "breeds.json" {
+		t.Errorf("snapshot directory holds %v, want only breeds.json", entries)
+	}
+
+	loaded := NewBreeds(nil, cfg)
+	if err := loaded.loadSnapshot(); err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded.Cache) != 2 {
+		t.Errorf("loaded %d breeds, want 2", len(loaded.Cache))
+	}
+	for _, name := range []string{"Siamese", "Bengal"} {
+		if _, ok := loaded.Cache[normalizeBreed(name)]; !ok {
+			t.Errorf("breed %q wasn't loaded", name)
+		}
+	}
+	if !loaded.checkedAt.IsZero() {
+		t.Errorf("loaded snapshot checked at %v, want it stale", loaded.checkedAt)
+	}
+}
+
+func TestLoadSnapshotErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+		data string
+	}{
+		{name: "disabled"},
+		{name: "missing", path: "breeds.json"},
+		{name: "corrupt", path: "breeds.json", data: "[{"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var path string
+			if tt.path != "" {
+				path = filepath.Join(t.TempDir(), tt.path)
+			}
+			if tt.data != "" {
+				if err := os.WriteFile(path, []byte(tt.data), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			b := NewBreeds(nil, BreedsConfig{SnapshotPath: path})
+			if err := b.loadSnapshot(); err == nil {
+				t.Error("loadSnapshot() succeeded, want an error")
+			}
+		})
+	}
+}
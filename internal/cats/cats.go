@@ -2,86 +2,20 @@ package cats
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"io"
 	"log/slog"
-	"net/http"
-	"sync"
 	"time"
 
 	"spy-cat-agency/internal/models"
 	"spy-cat-agency/internal/validator"
 )
 
-type Breed struct {
-	ID   string `json:"id"`
-	Name string `json:"name"`
-}
-
-type Breeds struct {
-	Api   string
-	Cache map[string]struct{}
-	mu    sync.RWMutex
-}
-
 type Service struct {
 	Repo
 	*Breeds
 	Validate *validator.Validator
 }
 
-func NewBreeds(breedsApi string) (*Breeds, error) {
-	b := &Breeds{
-		Api:   breedsApi,
-		Cache: map[string]struct{}{},
-		mu:    sync.RWMutex{},
-	}
-	if err := b.Fetch(); err != nil {
-		return nil, err
-	}
-
-	return b, nil
-}
-
-func (b *Breeds) Exists(breed string) bool {
-	b.mu.RLock()
-	defer b.mu.RUnlock()
-
-	_, found := b.Cache[breed]
-	return found
-}
-
-func (b *Breeds) Fetch() error {
-	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Get(b.Api)
-	if err != nil {
-		return fmt.Errorf("Error executing breeds API request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return fmt.Errorf("Error reading breeds API response body: %w", err)
-	}
-
-	var breeds []Breed
-	if err := json.Unmarshal(body, &breeds); err != nil {
-		return fmt.Errorf("Error unmarshaling breeds API response: %w", err)
-	}
-
-	b.mu.Lock()
-	defer b.mu.Unlock()
-
-	b.Cache = make(map[string]struct{})
-	for _, breed := range breeds {
-		b.Cache[breed.Name] = struct{}{}
-	}
-
-	slog.Info("Breeds cache populated", "breeds", len(b.Cache))
-	return nil
-}
-
 func (s *Service) Create(ctx context.Context, cat *models.Cat) (int64, error) {
 	id, err := s.Repo.Create(ctx, cat)
 	if err != nil {
@@ -93,18 +27,34 @@ func (s *Service) Create(ctx context.Context, cat *models.Cat) (int64, error) {
 	return id, nil
 }
 
-func (s *Service) Remove(ctx context.Context, id int) error {
-	return s.Repo.Remove(ctx, id)
+func (s *Service) Remove(ctx context.Context, id int, opts RemoveOptions) (*Removal, error) {
+	return s.Repo.Remove(ctx, id, opts)
+}
+
+func (s *Service) Restore(ctx context.Context, id int) (*models.Cat, error) {
+	return s.Repo.Restore(ctx, id)
+}
+
+func (s *Service) Update(ctx context.Context, id int, patch Patch) (*models.Cat, error) {
+	return s.Repo.Update(ctx, id, patch)
+}
+
+func (s *Service) UpdateSalary(ctx context.Context, update SalaryUpdate) (*models.SalaryChange, error) {
+	return s.Repo.UpdateSalary(ctx, update)
+}
+
+func (s *Service) SalaryHistory(ctx context.Context, catID int) ([]models.SalaryChange, error) {
+	return s.Repo.SalaryHistory(ctx, catID)
 }
 
-func (s *Service) UpdateSalary(ctx context.Context, cat *models.Cat) (*models.Cat, error) {
-	return s.Repo.UpdateSalary(ctx, cat)
+func (s *Service) SalaryChanges(ctx context.Context, from, to time.Time) ([]models.SalaryChange, error) {
+	return s.Repo.SalaryChanges(ctx, from, to)
 }
 
-func (s *Service) List(ctx context.Context) ([]models.Cat, error) {
-	return s.Repo.List(ctx)
+func (s *Service) List(ctx context.Context, filter Filter) (*Page, error) {
+	return s.Repo.List(ctx, filter)
 }
 
-func (s *Service) Get(ctx context.Context, id int) (*models.Cat, error) {
-	return s.Repo.Get(ctx, id)
+func (s *Service) Get(ctx context.Context, id int, withDeleted bool) (*models.Cat, error) {
+	return s.Repo.Get(ctx, id, withDeleted)
 }
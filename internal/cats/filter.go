@@ -0,0 +1,142 @@
+package cats
+
+import (
+	"fmt"
+	"strings"
+
+	"spy-cat-agency/internal/models"
+	"spy-cat-agency/internal/paging"
+	"spy-cat-agency/internal/validator"
+)
+
+// sortColumns maps the sort keys accepted by List to their columns.
+var sortColumns = map[string]string{
+	"id":     "id",
+	"name":   "name",
+	"salary": "salary",
+	"yoe":    "years_of_experience",
+}
+
+// Filter narrows down and orders the cats returned by List.
+type Filter struct {
+	Breed         string
+	NamePrefix    string
+	MinSalary     *models.Money
+	MaxSalary     *models.Money
+	MinExperience *int
+	MaxExperience *int
+	Status        models.CatStatus
+	// IncludeDeleted also lists removed cats.
+	IncludeDeleted bool
+	// Sort is one of the sort keys, prefixed with "-" for descending order.
+	Sort   string
+	Cursor string
+	Limit  int
+}
+
+// Page is a single page of cats.
+type Page struct {
+	Cats       []models.Cat `json:"cats"`
+	Total      int          `json:"total"`
+	NextCursor string       `json:"next_cursor,omitempty"`
+}
+
+// cursor is the position of the last cat of a page.
+type cursor struct {
+	Sort  string `json:"s"`
+	Value string `json:"v"`
+	ID    int64  `json:"id"`
+}
+
+func (f *Filter) Validate(v *validator.Validator) {
+	_, _, ok := f.order()
+	v.Check(ok, "sort", "unknown sort key")
+	v.Check(f.Status == "" || validCatStatus(f.Status), "status", fmt.Sprintf("unknown status %q", f.Status))
+	v.Check(f.Limit >= 0 && f.Limit <= paging.MaxLimit, "limit", fmt.Sprintf("must be between 1 and %d", paging.MaxLimit))
+	if f.MinSalary != nil && f.MaxSalary != nil {
+		v.Check(*f.MinSalary <= *f.MaxSalary, "min_salary", "can't be greater than max_salary")
+	}
+	if f.MinExperience != nil && f.MaxExperience != nil {
+		v.Check(*f.MinExperience <= *f.MaxExperience, "min_yoe", "can't be greater than max_yoe")
+	}
+	if f.Cursor != "" {
+		var c cursor
+		err := paging.DecodeCursor(f.Cursor, &c)
+		v.Check(err == nil && c.Sort == f.sortKey(), "cursor", paging.ErrInvalidCursor.Error())
+	}
+}
+
+func (f *Filter) sortKey() string {
+	if f.Sort == "" {
+		return "id"
+	}
+	return f.Sort
+}
+
+// order returns the sort column and whether the order is descending.
+func (f *Filter) order() (string, bool, bool) {
+	key, desc := strings.CutPrefix(f.sortKey(), "-")
+	column, ok := sortColumns[key]
+	return column, desc, ok
+}
+
+// where builds the WHERE clause for the filters, without the cursor.
+func (f *Filter) where() (string, []any) {
+	var (
+		conds []string
+		args  []any
+	)
+	add := func(cond string, arg any) {
+		args = append(args, arg)
+		conds = append(conds, fmt.Sprintf(cond, len(args)))
+	}
+
+	if !f.IncludeDeleted {
+		conds = append(conds, "deleted_at IS NULL")
+	}
+	if f.Status != "" {
+		add(statusColumn+" = $%d", f.Status)
+	}
+	if f.Breed != "" {
+		add("breed = $%d", f.Breed)
+	}
+	if f.NamePrefix != "" {
+		add("name ILIKE $%d || '%%'", escapeLike(f.NamePrefix))
+	}
+	if f.MinSalary != nil {
+		add("salary >= $%d", *f.MinSalary)
+	}
+	if f.MaxSalary != nil {
+		add("salary <= $%d", *f.MaxSalary)
+	}
+	if f.MinExperience != nil {
+		add("years_of_experience >= $%d", *f.MinExperience)
+	}
+	if f.MaxExperience != nil {
+		add("years_of_experience <= $%d", *f.MaxExperience)
+	}
+
+	if len(conds) == 0 {
+		return "", args
+	}
+
+	return "WHERE " + strings.Join(conds, " AND "), args
+}
+
+// cursorValue returns the value of the sort column for a cat, as stored in cursors.
+func cursorValue(column string, cat *models.Cat) string {
+	switch column {
+	case "name":
+		return cat.Name
+	case "salary":
+		return cat.Salary.String()
+	case "years_of_experience":
+		return fmt.Sprint(cat.YearsOfExperience)
+	default:
+		return fmt.Sprint(cat.ID)
+	}
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
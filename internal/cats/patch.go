@@ -0,0 +1,15 @@
+package cats
+
+import "spy-cat-agency/internal/models"
+
+// Patch holds the cat fields to change, nil fields are left untouched.
+type Patch struct {
+	Name              *string            `json:"name"`
+	YearsOfExperience *int8              `json:"yoe"`
+	Breed             *string            `json:"breed"`
+	DutyStatus        *models.DutyStatus `json:"duty_status" swaggertype:"string" enums:"active,on_leave,retired"`
+}
+
+func (p *Patch) Empty() bool {
+	return p.Name == nil && p.YearsOfExperience == nil && p.Breed == nil && p.DutyStatus == nil
+}
@@ -0,0 +1,154 @@
+package cats
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	ProviderAPI  = "api"
+	ProviderFile = "file"
+	ProviderDB   = "db"
+)
+
+// BreedProvider supplies the catalog of known breeds.
+type BreedProvider interface {
+	Breeds(ctx context.Context) ([]Breed, error)
+}
+
+// NewBreedProvider returns the provider selected by cfg.Provider.
+func NewBreedProvider(cfg BreedsConfig, db *sql.DB) (BreedProvider, error) {
+	switch cfg.Provider {
+	case ProviderAPI, "":
+		return NewAPIProvider(cfg.Api), nil
+	case ProviderFile:
+		return NewFileProvider(cfg.File), nil
+	case ProviderDB:
+		return NewDBProvider(db), nil
+	default:
+		return nil, fmt.Errorf("Unknown breeds provider %q", cfg.Provider)
+	}
+}
+
+// APIProvider fetches breeds from TheCatAPI over HTTP.
+type APIProvider struct {
+	Url    string
+	Client *http.Client
+}
+
+func NewAPIProvider(url string) *APIProvider {
+	return &APIProvider{
+		Url:    url,
+		Client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (p *APIProvider) Breeds(ctx context.Context) ([]Breed, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.Url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("Error creating breeds API request: %w", err)
+	}
+
+	resp, err := p.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("Error executing breeds API request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Breeds API responded with status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading breeds API response body: %w", err)
+	}
+
+	var breeds []Breed
+	if err := json.Unmarshal(body, &breeds); err != nil {
+		return nil, fmt.Errorf("Error unmarshaling breeds API response: %w", err)
+	}
+
+	return breeds, nil
+}
+
+// FileProvider reads breeds from a local JSON or YAML file,
+// the format is picked by the file extension.
+type FileProvider struct {
+	Path string
+}
+
+func NewFileProvider(path string) *FileProvider {
+	return &FileProvider{Path: path}
+}
+
+func (p *FileProvider) Breeds(ctx context.Context) ([]Breed, error) {
+	data, err := os.ReadFile(p.Path)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading breeds file: %w", err)
+	}
+
+	var breeds []Breed
+	switch strings.ToLower(filepath.Ext(p.Path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &breeds)
+	default:
+		err = json.Unmarshal(data, &breeds)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Error unmarshaling breeds file: %w", err)
+	}
+
+	return breeds, nil
+}
+
+// DBProvider reads breeds from the breeds table.
+type DBProvider struct {
+	DB *sql.DB
+}
+
+func NewDBProvider(db *sql.DB) *DBProvider {
+	return &DBProvider{DB: db}
+}
+
+func (p *DBProvider) Breeds(ctx context.Context) ([]Breed, error) {
+	query := `
+		SELECT id, name, temperament, origin, life_span, weight_imperial, weight_metric
+		FROM breeds
+		ORDER BY name
+	`
+	rows, err := p.DB.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("Error querying breeds table: %w", err)
+	}
+	defer rows.Close()
+
+	var breeds []Breed
+	for rows.Next() {
+		var breed Breed
+		if err := rows.Scan(
+			&breed.ID,
+			&breed.Name,
+			&breed.Temperament,
+			&breed.Origin,
+			&breed.LifeSpan,
+			&breed.Weight.Imperial,
+			&breed.Weight.Metric,
+		); err != nil {
+			return nil, err
+		}
+		breeds = append(breeds, breed)
+	}
+
+	return breeds, rows.Err()
+}
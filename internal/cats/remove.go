@@ -0,0 +1,244 @@
+package cats
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"spy-cat-agency/internal/missions"
+	"spy-cat-agency/internal/models"
+	"spy-cat-agency/internal/storage"
+
+	"github.com/lib/pq"
+)
+
+// RemoveOptions decide what happens to the active missions of a removed cat.
+// Without either option a cat on an active mission can't be removed.
+type RemoveOptions struct {
+	// ReplacementID is the cat that takes over the active missions.
+	ReplacementID int64
+	// Orphan unassigns the active missions, moving them back to draft.
+	// Missions already in progress can't be orphaned.
+	Orphan bool
+}
+
+// Removal lists the active missions of a removed cat and where they went.
+type Removal struct {
+	ReassignedMissions []int `json:"reassigned_missions,omitempty"`
+	OrphanedMissions   []int `json:"orphaned_missions,omitempty"`
+}
+
+// activeStatuses are the statuses of missions a cat is working on.
+var activeStatuses = pq.Array([]string{
+	string(models.MissionAssigned),
+	string(models.MissionInProgress),
+})
+
+// Remove archives a cat. Its active missions are handed over to the
+// replacement or orphaned according to opts, within the same transaction.
+func (s *Repository) Remove(ctx context.Context, id int, opts RemoveOptions) (*Removal, error) {
+	var removal *Removal
+	err := storage.WithTx(ctx, s.DB, nil, func(tx *sql.Tx) error {
+		catQuery := `
+			SELECT id FROM cats WHERE id = $1 AND deleted_at IS NULL FOR UPDATE
+		`
+		if err := tx.QueryRowContext(ctx, catQuery, id).Scan(&id); err != nil {
+			return err
+		}
+
+		missionIDs, err := activeMissions(ctx, tx, int64(id))
+		if err != nil {
+			slog.Error("Remove cat", "active missions", err)
+			return err
+		}
+
+		removal = &Removal{}
+		switch {
+		case len(missionIDs) == 0:
+		case opts.ReplacementID != 0:
+			if err := checkReplacement(ctx, tx, int64(id), opts.ReplacementID); err != nil {
+				return err
+			}
+			reassignQuery := `
+				UPDATE missions SET cat_id = $1 WHERE id = ANY($2)
+			`
+			if _, err := tx.ExecContext(ctx, reassignQuery, opts.ReplacementID, pq.Array(missionIDs)); err != nil {
+				slog.Error("Remove cat", "reassign missions", err)
+				if storage.IsUniqueViolation(err, "missions_one_active_per_cat_idx") {
+					return ErrReplacementBusy
+				}
+				return err
+			}
+			if err := handOver(ctx, tx, missionIDs, opts.ReplacementID); err != nil {
+				return err
+			}
+			removal.ReassignedMissions = missionIDs
+		case opts.Orphan:
+			if err := checkOrphan(ctx, tx, missionIDs); err != nil {
+				return err
+			}
+			orphanQuery := `
+				UPDATE missions SET cat_id = NULL, status = $1 WHERE id = ANY($2)
+			`
+			if _, err := tx.ExecContext(ctx, orphanQuery, models.MissionDraft, pq.Array(missionIDs)); err != nil {
+				slog.Error("Remove cat", "orphan missions", err)
+				return err
+			}
+			if err := handOver(ctx, tx, missionIDs, 0); err != nil {
+				return err
+			}
+			removal.OrphanedMissions = missionIDs
+		default:
+			return ErrActiveMission
+		}
+
+		removeQuery := `
+			UPDATE cats SET deleted_at = NOW() WHERE id = $1
+		`
+		if _, err := tx.ExecContext(ctx, removeQuery, id); err != nil {
+			slog.Error("Remove cat", "exec context", err)
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return removal, nil
+}
+
+// activeMissions locks and returns the active missions of a cat.
+func activeMissions(ctx context.Context, tx *sql.Tx, catID int64) ([]int, error) {
+	query := `
+		SELECT id FROM missions
+		WHERE cat_id = $1 AND status = ANY($2)
+		ORDER BY id
+		FOR UPDATE
+	`
+	rows, err := tx.QueryContext(ctx, query, catID, activeStatuses)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := []int{}
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+// checkOrphan makes sure the missions can go back to draft without a cat.
+func checkOrphan(ctx context.Context, tx *sql.Tx, missionIDs []int) error {
+	query := `
+		SELECT id, status FROM missions WHERE id = ANY($1) ORDER BY id
+	`
+	rows, err := tx.QueryContext(ctx, query, pq.Array(missionIDs))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			id     int
+			status models.MissionStatus
+		)
+		if err := rows.Scan(&id, &status); err != nil {
+			return err
+		}
+		if !missions.CanTransition(status, models.MissionDraft) {
+			return fmt.Errorf("%w: mission %d is %s", ErrCannotOrphan, id, status)
+		}
+	}
+
+	return rows.Err()
+}
+
+// checkReplacement makes sure the replacement cat is available to take over the missions.
+func checkReplacement(ctx context.Context, tx *sql.Tx, catID, replacementID int64) error {
+	if replacementID == catID {
+		return ErrReplacementNotFound
+	}
+
+	replacementQuery := `
+		SELECT c.duty_status <> 'active' OR EXISTS(
+			SELECT 1 FROM missions m
+			WHERE m.cat_id = c.id AND m.status = ANY($2)
+		)
+		FROM cats c
+		WHERE c.id = $1 AND c.deleted_at IS NULL
+		FOR UPDATE OF c
+	`
+	var busy bool
+	err := tx.QueryRowContext(ctx, replacementQuery, replacementID, activeStatuses).Scan(&busy)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrReplacementNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if busy {
+		return ErrReplacementBusy
+	}
+
+	return nil
+}
+
+// handOver closes the assignments of the removed cat's missions in the
+// assignment history and opens them for the replacement, if any.
+func handOver(ctx context.Context, tx *sql.Tx, missionIDs []int, replacementID int64) error {
+	closeQuery := `
+		UPDATE mission_assignments SET unassigned_at = NOW(), unassign_reason = 'cat_removed'
+		WHERE mission_id = ANY($1) AND unassigned_at IS NULL
+	`
+	if _, err := tx.ExecContext(ctx, closeQuery, pq.Array(missionIDs)); err != nil {
+		return err
+	}
+	if replacementID == 0 {
+		return nil
+	}
+
+	openQuery := `
+		INSERT INTO mission_assignments (mission_id, cat_id)
+		SELECT id, $2 FROM UNNEST($1::int[]) AS id
+	`
+	_, err := tx.ExecContext(ctx, openQuery, pq.Array(missionIDs), replacementID)
+	return err
+}
+
+// Restore brings back a removed cat. It fails with sql.ErrNoRows if the
+// cat doesn't exist and ErrNotRemoved if it was never removed.
+func (s *Repository) Restore(ctx context.Context, id int) (*models.Cat, error) {
+	query := `
+		WITH restored AS (
+			UPDATE cats SET deleted_at = NULL
+			WHERE id = $1 AND deleted_at IS NOT NULL
+			RETURNING *
+		)
+		SELECT ` + catColumns + `
+		FROM ` + fromCats("restored")
+	var cat models.Cat
+	err := scanCat(s.DB.QueryRowContext(ctx, query, id), &cat)
+	if errors.Is(err, sql.ErrNoRows) {
+		if _, err := s.Get(ctx, id, true); err != nil {
+			return nil, err
+		}
+		return nil, ErrNotRemoved
+	}
+	if err != nil {
+		slog.Error("Restore cat", "query exec", err)
+		return nil, err
+	}
+
+	return &cat, nil
+}
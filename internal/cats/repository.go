@@ -3,19 +3,36 @@ package cats
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"fmt"
 	"log/slog"
+	"time"
 
 	"spy-cat-agency/internal/models"
+	"spy-cat-agency/internal/paging"
+	"spy-cat-agency/internal/storage"
 )
 
 type Repo interface {
 	Create(ctx context.Context, cat *models.Cat) (int64, error)
-	Get(ctx context.Context, id int) (*models.Cat, error)
-	List(ctx context.Context) ([]models.Cat, error)
-	Remove(ctx context.Context, id int) error
-	UpdateSalary(ctx context.Context, cat *models.Cat) (*models.Cat, error)
+	Get(ctx context.Context, id int, withDeleted bool) (*models.Cat, error)
+	List(ctx context.Context, filter Filter) (*Page, error)
+	Remove(ctx context.Context, id int, opts RemoveOptions) (*Removal, error)
+	Restore(ctx context.Context, id int) (*models.Cat, error)
+	Update(ctx context.Context, id int, patch Patch) (*models.Cat, error)
+	UpdateSalary(ctx context.Context, update SalaryUpdate) (*models.SalaryChange, error)
+	SalaryHistory(ctx context.Context, catID int) ([]models.SalaryChange, error)
+	SalaryChanges(ctx context.Context, from, to time.Time) ([]models.SalaryChange, error)
 }
 
+var (
+	ErrActiveMission       = errors.New("Cat is on an active mission, name a replacement or orphan the mission")
+	ErrReplacementNotFound = errors.New("Replacement cat not found")
+	ErrReplacementBusy     = errors.New("Replacement cat isn't available")
+	ErrCannotOrphan        = errors.New("Mission can't go back to draft, name a replacement instead")
+	ErrNotRemoved          = errors.New("Cat isn't removed")
+)
+
 func NewRepository(db *sql.DB) *Repository {
 	return &Repository{
 		DB: db,
@@ -28,8 +45,8 @@ type Repository struct {
 
 func (s *Repository) Create(ctx context.Context, cat *models.Cat) (int64, error) {
 	query := `
-		INSERT INTO cats (name, years_of_experience, breed, salary)
-		VALUES ($1, $2, $3, $4)
+		INSERT INTO cats (name, years_of_experience, breed, salary, currency)
+		VALUES ($1, $2, $3, $4, $5)
 		RETURNING id
 	`
 	var id int64
@@ -38,6 +55,7 @@ func (s *Repository) Create(ctx context.Context, cat *models.Cat) (int64, error)
 		cat.YearsOfExperience,
 		cat.Breed,
 		cat.Salary,
+		cat.Currency,
 	).Scan(&id); err != nil {
 		return 0, err
 	}
@@ -45,60 +63,105 @@ func (s *Repository) Create(ctx context.Context, cat *models.Cat) (int64, error)
 	return id, nil
 }
 
-func (s *Repository) Remove(ctx context.Context, id int) error {
+// Update changes the patched fields of a cat. A cat on an active mission
+// can't go off duty, that fails with ErrOnMission. The cat row is locked
+// first, so it can't be assigned while it goes off duty.
+func (s *Repository) Update(ctx context.Context, id int, patch Patch) (*models.Cat, error) {
 	query := `
-		DELETE FROM cats WHERE id = $1
+		WITH updated AS (
+			UPDATE cats SET
+				name = COALESCE($1, name),
+				years_of_experience = COALESCE($2, years_of_experience),
+				breed = COALESCE($3, breed),
+				duty_status = COALESCE($5, duty_status)
+			WHERE id = $4 AND deleted_at IS NULL
+			RETURNING *
+		)
+		SELECT ` + catColumns + `
+		FROM ` + fromCats("updated")
+	lockQuery := `
+		SELECT EXISTS(
+			SELECT 1 FROM missions m
+			WHERE m.cat_id = c.id AND m.status = ANY($2)
+		)
+		FROM cats c
+		WHERE c.id = $1 AND c.deleted_at IS NULL
+		FOR UPDATE OF c
 	`
-	result, err := s.DB.ExecContext(ctx, query, id)
-	if err != nil {
-		slog.Info("Remove cat", "exec context", err)
-		return err
-	}
 
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		return err
-	}
+	var cat models.Cat
+	err := storage.WithTx(ctx, s.DB, nil, func(tx *sql.Tx) error {
+		var onMission bool
+		if err := tx.QueryRowContext(ctx, lockQuery, id, activeStatuses).Scan(&onMission); err != nil {
+			return err
+		}
+		if onMission && patch.DutyStatus != nil && *patch.DutyStatus != models.DutyActive {
+			return ErrOnMission
+		}
 
-	if rowsAffected == 0 {
-		return sql.ErrNoRows
+		return scanCat(tx.QueryRowContext(ctx, query,
+			patch.Name,
+			patch.YearsOfExperience,
+			patch.Breed,
+			id,
+			patch.DutyStatus,
+		), &cat)
+	})
+	if err != nil {
+		slog.Error("Update cat", "query exec", err)
+		return nil, err
 	}
 
-	return nil
+	return &cat, nil
 }
 
-func (s *Repository) UpdateSalary(ctx context.Context, cat *models.Cat) (*models.Cat, error) {
-	updateQuery := `
-		UPDATE cats SET salary = $1 WHERE id = $2
-	`
-
+// UpdateSalary changes a cat's salary and records the change in the
+// salary ledger within the same transaction.
+func (s *Repository) UpdateSalary(ctx context.Context, update SalaryUpdate) (*models.SalaryChange, error) {
 	tx, err := s.DB.BeginTx(ctx, nil)
 	if err != nil {
 		return nil, err
 	}
 	defer func() { _ = tx.Rollback() }()
 
-	result, err := tx.Exec(updateQuery, cat.Salary, cat.ID)
-	if err != nil {
-		slog.Error("UpdateSalary", "update query exec error", err)
-		return nil, err
+	change := &models.SalaryChange{
+		CatID:     update.ID,
+		NewSalary: update.Salary,
+		Reason:    update.Reason,
+		Actor:     update.Actor,
 	}
 
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
+	oldSalaryQuery := `
+		SELECT salary, currency FROM cats WHERE id = $1 AND deleted_at IS NULL FOR UPDATE
+	`
+	if err = tx.QueryRowContext(ctx, oldSalaryQuery, update.ID).Scan(&change.OldSalary, &change.Currency); err != nil {
+		slog.Error("UpdateSalary", "old salary query", err)
 		return nil, err
 	}
-	if rowsAffected == 0 {
-		return nil, sql.ErrNoRows
-	}
 
-	getQuery := `
-		SELECT salary FROM cats WHERE id = $1
+	updateQuery := `
+		UPDATE cats SET salary = $1 WHERE id = $2
+		RETURNING salary
 	`
+	if err = tx.QueryRowContext(ctx, updateQuery, update.Salary, update.ID).Scan(&change.NewSalary); err != nil {
+		slog.Error("UpdateSalary", "update query exec error", err)
+		return nil, err
+	}
 
-	updatedCat := *cat
-	if err = tx.QueryRow(getQuery, cat.ID).Scan(&updatedCat.Salary); err != nil {
-		slog.Error("Get updated salary cat", "error", err)
+	ledgerQuery := `
+		INSERT INTO salary_changes (cat_id, old_salary, new_salary, currency, reason, actor)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, changed_at
+	`
+	if err = tx.QueryRowContext(ctx, ledgerQuery,
+		change.CatID,
+		change.OldSalary,
+		change.NewSalary,
+		change.Currency,
+		change.Reason,
+		change.Actor,
+	).Scan(&change.ID, &change.ChangedAt); err != nil {
+		slog.Error("UpdateSalary", "ledger insert", err)
 		return nil, err
 	}
 
@@ -107,46 +170,95 @@ func (s *Repository) UpdateSalary(ctx context.Context, cat *models.Cat) (*models
 		return nil, err
 	}
 
-	return &updatedCat, nil
+	return change, nil
 }
 
-func (s *Repository) List(ctx context.Context) ([]models.Cat, error) {
-	query := `
-		SELECT id, name, years_of_experience, breed, salary
-		FROM cats
-	`
-	rows, err := s.DB.QueryContext(ctx, query)
+func (s *Repository) List(ctx context.Context, filter Filter) (*Page, error) {
+	column, desc, ok := filter.order()
+	if !ok {
+		return nil, fmt.Errorf("unknown sort key %q", filter.Sort)
+	}
+
+	where, args := filter.where()
+
+	page := &Page{Cats: []models.Cat{}}
+	countQuery := `SELECT COUNT(*) FROM ` + fromCats("cats") + ` ` + where
+	if err := s.DB.QueryRowContext(ctx, countQuery, args...).Scan(&page.Total); err != nil {
+		slog.Error("List cats", "count query", err)
+		return nil, err
+	}
+
+	direction, cmp := "ASC", ">"
+	if desc {
+		direction, cmp = "DESC", "<"
+	}
+
+	if filter.Cursor != "" {
+		var c cursor
+		if err := paging.DecodeCursor(filter.Cursor, &c); err != nil {
+			return nil, err
+		}
+		args = append(args, c.Value, c.ID)
+		keyset := fmt.Sprintf("(c.%s, c.id) %s ($%d, $%d)", column, cmp, len(args)-1, len(args))
+		if where == "" {
+			where = "WHERE " + keyset
+		} else {
+			where += " AND " + keyset
+		}
+	}
+
+	limit := paging.Limit(filter.Limit)
+	args = append(args, limit+1)
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM %s
+		%s
+		ORDER BY c.%s %s, c.id %s
+		LIMIT $%d
+	`, catColumns, fromCats("cats"), where, column, direction, direction, len(args))
+
+	rows, err := s.DB.QueryContext(ctx, query, args...)
 	if err != nil {
 		slog.Error("List cats", "query context", err)
 		return nil, err
 	}
 	defer rows.Close()
 
-	var cats []models.Cat
 	for rows.Next() {
 		var cat models.Cat
-		if err := rows.Scan(&cat.ID, &cat.Name, &cat.YearsOfExperience, &cat.Breed, &cat.Salary); err != nil {
+		if err := scanCat(rows, &cat); err != nil {
 			slog.Error("List cats", "rows scan", err)
 			return nil, err
 		}
-		cats = append(cats, cat)
+		page.Cats = append(page.Cats, cat)
 	}
 
 	if err = rows.Err(); err != nil {
 		return nil, err
 	}
 
-	return cats, nil
+	if len(page.Cats) > limit {
+		page.Cats = page.Cats[:limit]
+		last := &page.Cats[limit-1]
+		page.NextCursor = paging.EncodeCursor(cursor{
+			Sort:  filter.sortKey(),
+			Value: cursorValue(column, last),
+			ID:    last.ID,
+		})
+	}
+
+	return page, nil
 }
 
-func (s *Repository) Get(ctx context.Context, id int) (*models.Cat, error) {
+// Get returns a cat, removed cats are only returned withDeleted.
+func (s *Repository) Get(ctx context.Context, id int, withDeleted bool) (*models.Cat, error) {
 	query := `
-		SELECT id, name, years_of_experience, breed, salary
-		FROM cats
-		WHERE id = $1
+		SELECT ` + catColumns + `
+		FROM ` + fromCats("cats") + `
+		WHERE c.id = $1 AND ($2 OR c.deleted_at IS NULL)
 	`
 	var cat models.Cat
-	err := s.DB.QueryRowContext(ctx, query, id).Scan(&cat.ID, &cat.Name, &cat.YearsOfExperience, &cat.Breed, &cat.Salary)
+	err := scanCat(s.DB.QueryRowContext(ctx, query, id, withDeleted), &cat)
 	if err != nil {
 		slog.Error("Get cat", "query exec", err)
 		return nil, err
@@ -0,0 +1,82 @@
+package cats
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"time"
+
+	"spy-cat-agency/internal/models"
+)
+
+// MaxSalary is the largest amount the salary columns can hold.
+const MaxSalary models.Money = 99_999_999_99
+
+// SalaryUpdate is a request to change a cat's salary.
+// The salary stays in the cat's currency.
+type SalaryUpdate struct {
+	ID     int64        `json:"id"`
+	Salary models.Money `json:"salary" swaggertype:"number"`
+	Reason string       `json:"reason"`
+	Actor  string       `json:"actor"`
+}
+
+func (s *Repository) SalaryHistory(ctx context.Context, catID int) ([]models.SalaryChange, error) {
+	var exists bool
+	existsQuery := `
+		SELECT EXISTS(SELECT 1 FROM cats WHERE id = $1)
+	`
+	if err := s.DB.QueryRowContext(ctx, existsQuery, catID).Scan(&exists); err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, sql.ErrNoRows
+	}
+
+	query := `
+		SELECT id, cat_id, old_salary, new_salary, currency, reason, actor, changed_at
+		FROM salary_changes
+		WHERE cat_id = $1
+		ORDER BY changed_at, id
+	`
+	return s.querySalaryChanges(ctx, query, catID)
+}
+
+func (s *Repository) SalaryChanges(ctx context.Context, from, to time.Time) ([]models.SalaryChange, error) {
+	query := `
+		SELECT id, cat_id, old_salary, new_salary, currency, reason, actor, changed_at
+		FROM salary_changes
+		WHERE changed_at >= $1 AND changed_at < $2
+		ORDER BY changed_at, id
+	`
+	return s.querySalaryChanges(ctx, query, from, to)
+}
+
+func (s *Repository) querySalaryChanges(ctx context.Context, query string, args ...any) ([]models.SalaryChange, error) {
+	rows, err := s.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		slog.Error("Salary changes", "query context", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	changes := []models.SalaryChange{}
+	for rows.Next() {
+		var change models.SalaryChange
+		if err := rows.Scan(
+			&change.ID,
+			&change.CatID,
+			&change.OldSalary,
+			&change.NewSalary,
+			&change.Currency,
+			&change.Reason,
+			&change.Actor,
+			&change.ChangedAt,
+		); err != nil {
+			return nil, err
+		}
+		changes = append(changes, change)
+	}
+
+	return changes, rows.Err()
+}
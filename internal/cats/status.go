@@ -0,0 +1,81 @@
+package cats
+
+import (
+	"errors"
+	"fmt"
+
+	"spy-cat-agency/internal/models"
+)
+
+var ErrOnMission = errors.New("Cat is on an active mission, it can't go off duty")
+
+// statusColumn derives the models.CatStatus of a cat joined by fromCats.
+const statusColumn = `
+	CASE
+		WHEN c.duty_status = 'retired' THEN 'retired'
+		WHEN am.mission_id IS NOT NULL THEN 'on_mission'
+		WHEN c.duty_status = 'on_leave' THEN 'on_leave'
+		ELSE 'available'
+	END`
+
+// catColumns are the columns read into a models.Cat by scanCat,
+// selected from a source joined by fromCats.
+const catColumns = `
+	c.id, c.name, c.years_of_experience, c.breed, c.salary, c.currency,
+	c.duty_status, am.mission_id, c.deleted_at,` + statusColumn
+
+// fromCats joins a source of cat rows, aliased c, with their active mission.
+func fromCats(source string) string {
+	return fmt.Sprintf(`%s c
+	LEFT JOIN LATERAL (
+		SELECT m.id AS mission_id FROM missions m
+		WHERE m.cat_id = c.id AND m.status IN ('assigned', 'in_progress')
+		ORDER BY m.created_at DESC
+		LIMIT 1
+	) am ON TRUE`, source)
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanCat(row scanner, cat *models.Cat) error {
+	var missionID *int64
+	if err := row.Scan(
+		&cat.ID,
+		&cat.Name,
+		&cat.YearsOfExperience,
+		&cat.Breed,
+		&cat.Salary,
+		&cat.Currency,
+		&cat.DutyStatus,
+		&missionID,
+		&cat.DeletedAt,
+		&cat.Status,
+	); err != nil {
+		return err
+	}
+	if missionID != nil {
+		cat.MissionID = int(*missionID)
+	}
+
+	return nil
+}
+
+func ValidDutyStatus(status models.DutyStatus) bool {
+	switch status {
+	case models.DutyActive, models.DutyOnLeave, models.DutyRetired:
+		return true
+	default:
+		return false
+	}
+}
+
+func validCatStatus(status models.CatStatus) bool {
+	switch status {
+	case models.CatAvailable, models.CatOnMission, models.CatOnLeave, models.CatRetired:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,83 @@
+// Package countries validates and normalizes country names and codes against
+// an embedded ISO 3166-1 dataset.
+package countries
+
+import (
+	_ "embed"
+	"encoding/json"
+	"strings"
+	"unicode"
+)
+
+//go:embed countries.json
+var dataset []byte
+
+// Country is an ISO 3166-1 country with the names it is commonly known by.
+type Country struct {
+	Alpha2  string   `json:"alpha2"`
+	Alpha3  string   `json:"alpha3"`
+	Name    string   `json:"name"`
+	Aliases []string `json:"aliases,omitempty"`
+}
+
+// index maps the normalized codes, names and aliases to their country.
+var index = load()
+
+func load() map[string]*Country {
+	var countries []*Country
+	if err := json.Unmarshal(dataset, &countries); err != nil {
+		panic("countries: invalid dataset: " + err.Error())
+	}
+
+	index := make(map[string]*Country, len(countries)*4)
+	for _, c := range countries {
+		for _, key := range append([]string{c.Alpha2, c.Alpha3, c.Name}, c.Aliases...) {
+			index[normalize(key)] = c
+		}
+	}
+
+	return index
+}
+
+// Lookup finds a country by its alpha-2 or alpha-3 code, its name or a common
+// alias such as "UK" or "Ivory Coast", ignoring case and punctuation.
+func Lookup(s string) (Country, bool) {
+	c, ok := index[normalize(s)]
+	if !ok {
+		return Country{}, false
+	}
+	return *c, true
+}
+
+// Name returns the display name of an alpha-2 code, or the code itself if it
+// isn't a known country.
+func Name(code string) string {
+	if c, ok := index[normalize(code)]; ok && c.Alpha2 == code {
+		return c.Name
+	}
+	return code
+}
+
+// normalize lowercases s, drops dots and apostrophes, and turns any other run
+// of characters that aren't letters or digits into a single space, so
+// "U.K." matches "uk" and "Guinea-Bissau" matches "guinea bissau".
+// The backfill migration normalizes the same way.
+func normalize(s string) string {
+	var b strings.Builder
+	space := false
+	for _, r := range strings.ToLower(s) {
+		switch {
+		case r == '.' || r == '\'' || r == '’':
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if space && b.Len() > 0 {
+				b.WriteByte(' ')
+			}
+			space = false
+			b.WriteRune(r)
+		default:
+			space = true
+		}
+	}
+
+	return b.String()
+}
@@ -0,0 +1,134 @@
+package countries
+
+import (
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// backfill is the migration normalizing the countries already stored.
+const backfill = "../storage/migrations/000019_normalize_target_countries.up.sql"
+
+func TestLookup(t *testing.T) {
+	tests := []struct {
+		in     string
+		want   string
+		wantOK bool
+	}{
+		{in: "UK", want: "GB", wantOK: true},
+		{in: "United Kingdom", want: "GB", wantOK: true},
+		{in: "england", want: "GB", wantOK: true},
+		{in: "U.K.", want: "GB", wantOK: true},
+		{in: "  gbr ", want: "GB", wantOK: true},
+		{in: "Guinea-Bissau", want: "GW", wantOK: true},
+		{in: "guinea bissau", want: "GW", wantOK: true},
+		{in: "Côte d’Ivoire", want: "CI", wantOK: true},
+		{in: "Narnia", wantOK: false},
+		{in: "", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, ok := Lookup(tt.in)
+			if ok != tt.wantOK || got.Alpha2 != tt.want {
+				t.Errorf("Lookup(%q) = %q, %v, want %q, %v", tt.in, got.Alpha2, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+// sqlNormalize mirrors the expression the backfill migration normalizes
+// with. Under the UTF-8 locale of the database, [:alnum:] matches any
+// letter or digit, not only ASCII ones.
+func sqlNormalize(s string) string {
+	s = regexp.MustCompile(`[.'’]`).ReplaceAllString(s, "")
+	s = regexp.MustCompile(`[^\pL\p{Nd}]+`).ReplaceAllString(s, " ")
+	return strings.ToLower(strings.TrimSpace(s))
+}
+
+func TestBackfillNormalizesLikeLookup(t *testing.T) {
+	sql, err := os.ReadFile(backfill)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const expr = `lower(trim(regexp_replace(regexp_replace(t.country, '[.''’]', '', 'g'), '[^[:alnum:]]+', ' ', 'g')))`
+	if n := strings.Count(string(sql), "a.alias = "+expr); n != 2 {
+		t.Fatalf("backfill normalizes %d tables with %s, want 2, update sqlNormalize if it changed", n, expr)
+	}
+
+	inputs := []string{"U.K.", "  United   Kingdom ", "Guinea-Bissau", "Côte d’Ivoire", "Saint-Barthélemy", "ÅLAND ISLANDS", "Bosnia & Herzegovina"}
+	for key := range index {
+		inputs = append(inputs, key)
+	}
+	for _, in := range inputs {
+		if got, want := sqlNormalize(in), normalize(in); got != want {
+			t.Errorf("backfill normalizes %q to %q, Lookup to %q", in, got, want)
+		}
+	}
+}
+
+func TestBackfillAliasesMatchDataset(t *testing.T) {
+	sql, err := os.ReadFile(backfill)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := valuesOf(t, string(sql), "country_aliases (alias, alpha2)")
+	want := make(map[string]string, len(index))
+	for key, c := range index {
+		want[key] = c.Alpha2
+	}
+	compareSets(t, "alias", got, want)
+
+	got = valuesOf(t, string(sql), "country_names (alpha2, name)")
+	want = make(map[string]string)
+	for _, c := range index {
+		want[c.Alpha2] = c.Name
+	}
+	compareSets(t, "name of", got, want)
+}
+
+// valuesOf parses the rows of two quoted strings inserted into table.
+func valuesOf(t *testing.T, sql, table string) map[string]string {
+	t.Helper()
+
+	start := strings.Index(sql, "INSERT INTO "+table+" VALUES")
+	if start < 0 {
+		t.Fatalf("backfill doesn't insert into %s", table)
+	}
+	end := strings.Index(sql[start:], ";")
+	if end < 0 {
+		t.Fatalf("backfill insert into %s isn't terminated", table)
+	}
+
+	rows := regexp.MustCompile(`\('((?:[^']|'')*)', '((?:[^']|'')*)'\)`).FindAllStringSubmatch(sql[start:start+end], -1)
+	values := make(map[string]string, len(rows))
+	for _, row := range rows {
+		key := strings.ReplaceAll(row[1], "''", "'")
+		if _, ok := values[key]; ok {
+			t.Errorf("%s has %q twice", table, key)
+		}
+		values[key] = strings.ReplaceAll(row[2], "''", "'")
+	}
+
+	return values
+}
+
+func compareSets(t *testing.T, what string, got, want map[string]string) {
+	t.Helper()
+
+	for key, w := range want {
+		if g, ok := got[key]; !ok {
+			t.Errorf("backfill is missing the %s %q", what, key)
+		} else if g != w {
+			t.Errorf("backfill %s %q is %q, want %q", what, key, g, w)
+		}
+	}
+	for key := range got {
+		if _, ok := want[key]; !ok {
+			t.Errorf("backfill has the %s %q, which isn't in the dataset", what, key)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package env
 import (
 	"os"
 	"strconv"
+	"time"
 )
 
 func GetString(key, fallback string) string {
@@ -27,3 +28,31 @@ func GetInt(key string, fallback int) int {
 
 	return valInt
 }
+
+func GetDuration(key string, fallback time.Duration) time.Duration {
+	val, ok := os.LookupEnv(key)
+	if !ok {
+		return fallback
+	}
+
+	valDuration, err := time.ParseDuration(val)
+	if err != nil {
+		return fallback
+	}
+
+	return valDuration
+}
+
+func GetBool(key string, fallback bool) bool {
+	val, ok := os.LookupEnv(key)
+	if !ok {
+		return fallback
+	}
+
+	valBool, err := strconv.ParseBool(val)
+	if err != nil {
+		return fallback
+	}
+
+	return valBool
+}
@@ -0,0 +1,116 @@
+// Package matching finds optimal one-to-one assignments.
+package matching
+
+import "math"
+
+// forbidden is the cost of a pair that can't be matched. It outweighs any
+// sum of allowed costs, so as many allowed pairs as possible are matched.
+const forbidden = 1e12
+
+// MaxWeight returns the one-to-one matching of rows to columns with the
+// largest total weight, using the Hungarian algorithm in O(n²m). Negative
+// weights mark pairs that can't be matched. The result holds the column
+// matched to every row, or -1 if the row is left unmatched.
+func MaxWeight(weights [][]float64) []int {
+	rows := len(weights)
+	if rows == 0 {
+		return nil
+	}
+	cols := len(weights[0])
+
+	// The algorithm needs at least as many columns as rows
+	if rows > cols {
+		transposed := make([][]float64, cols)
+		for j := range transposed {
+			transposed[j] = make([]float64, rows)
+			for i := range rows {
+				transposed[j][i] = weights[i][j]
+			}
+		}
+
+		match := make([]int, rows)
+		for i := range match {
+			match[i] = -1
+		}
+		for j, i := range MaxWeight(transposed) {
+			if i >= 0 {
+				match[i] = j
+			}
+		}
+		return match
+	}
+
+	var maxWeight float64
+	for _, row := range weights {
+		for _, w := range row {
+			maxWeight = max(maxWeight, w)
+		}
+	}
+	cost := func(i, j int) float64 {
+		if weights[i][j] < 0 {
+			return forbidden
+		}
+		return maxWeight - weights[i][j]
+	}
+
+	// Potentials and matching are 1-indexed, 0 is a virtual column
+	u := make([]float64, rows+1)
+	v := make([]float64, cols+1)
+	colRow := make([]int, cols+1)
+	way := make([]int, cols+1)
+
+	for i := 1; i <= rows; i++ {
+		colRow[0] = i
+		j0 := 0
+		minv := make([]float64, cols+1)
+		used := make([]bool, cols+1)
+		for j := range minv {
+			minv[j] = math.Inf(1)
+		}
+
+		for colRow[j0] != 0 {
+			used[j0] = true
+			i0, delta, j1 := colRow[j0], math.Inf(1), 0
+			for j := 1; j <= cols; j++ {
+				if used[j] {
+					continue
+				}
+				if cur := cost(i0-1, j-1) - u[i0] - v[j]; cur < minv[j] {
+					minv[j] = cur
+					way[j] = j0
+				}
+				if minv[j] < delta {
+					delta = minv[j]
+					j1 = j
+				}
+			}
+			for j := 0; j <= cols; j++ {
+				if used[j] {
+					u[colRow[j]] += delta
+					v[j] -= delta
+				} else {
+					minv[j] -= delta
+				}
+			}
+			j0 = j1
+		}
+
+		for j0 != 0 {
+			j1 := way[j0]
+			colRow[j0] = colRow[j1]
+			j0 = j1
+		}
+	}
+
+	match := make([]int, rows)
+	for i := range match {
+		match[i] = -1
+	}
+	for j := 1; j <= cols; j++ {
+		if i := colRow[j]; i != 0 && weights[i-1][j-1] >= 0 {
+			match[i-1] = j - 1
+		}
+	}
+
+	return match
+}
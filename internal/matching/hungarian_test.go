@@ -0,0 +1,145 @@
+package matching
+
+import (
+	"math"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func TestMaxWeight(t *testing.T) {
+	tests := []struct {
+		name    string
+		weights [][]float64
+		want    []int
+	}{
+		{name: "empty", weights: nil, want: nil},
+		{
+			name:    "greedy pick is wrong",
+			weights: [][]float64{{10, 9}, {9, 1}},
+			want:    []int{1, 0},
+		},
+		{
+			name:    "more columns than rows",
+			weights: [][]float64{{1, 5, 3}, {4, 6, 2}},
+			want:    []int{1, 0},
+		},
+		{
+			name:    "more rows than columns",
+			weights: [][]float64{{1}, {7}, {3}},
+			want:    []int{-1, 0, -1},
+		},
+		{
+			name:    "forbidden pairs",
+			weights: [][]float64{{-1, -1}, {-1, 2}},
+			want:    []int{-1, 1},
+		},
+		{
+			name:    "matches as many rows as possible first",
+			weights: [][]float64{{50, 1}, {-1, 49}},
+			want:    []int{0, 1},
+		},
+		{
+			name:    "more matches beat a heavier pair",
+			weights: [][]float64{{100, 1}, {1, -1}},
+			want:    []int{1, 0},
+		},
+		{
+			name:    "zero weights are matched",
+			weights: [][]float64{{0, 0}, {0, 0}},
+			want:    []int{0, 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := MaxWeight(tt.weights)
+			count, total := score(t, tt.weights, got)
+			wantCount, wantTotal := score(t, tt.weights, tt.want)
+			if count != wantCount || math.Abs(total-wantTotal) > 1e-9 {
+				t.Errorf("MaxWeight() = %v matching %d for %v, want %v matching %d for %v",
+					got, count, total, tt.want, wantCount, wantTotal)
+			}
+		})
+	}
+}
+
+func TestMaxWeightMatchesBruteForce(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	for range 500 {
+		rows, cols := 1+rng.IntN(5), 1+rng.IntN(5)
+		weights := make([][]float64, rows)
+		for i := range weights {
+			weights[i] = make([]float64, cols)
+			for j := range weights[i] {
+				weights[i][j] = float64(rng.IntN(100))
+				if rng.IntN(4) == 0 {
+					weights[i][j] = -1
+				}
+			}
+		}
+
+		got := MaxWeight(weights)
+		count, total := score(t, weights, got)
+		wantCount, wantTotal := bruteForce(weights, 0, make([]bool, cols))
+		if count != wantCount || math.Abs(total-wantTotal) > 1e-9 {
+			t.Fatalf("MaxWeight(%v) = %v matching %d for %v, want %d for %v",
+				weights, got, count, total, wantCount, wantTotal)
+		}
+	}
+}
+
+// score checks a matching is one-to-one without forbidden pairs, and returns
+// how many rows it matches and their total weight.
+func score(t *testing.T, weights [][]float64, match []int) (int, float64) {
+	t.Helper()
+
+	if len(match) != len(weights) {
+		t.Fatalf("matching %v has %d rows, want %d", match, len(match), len(weights))
+	}
+	var (
+		count int
+		total float64
+		seen  []int
+	)
+	for i, j := range match {
+		if j < 0 {
+			continue
+		}
+		if slices.Contains(seen, j) {
+			t.Fatalf("matching %v uses column %d twice", match, j)
+		}
+		if weights[i][j] < 0 {
+			t.Fatalf("matching %v uses the forbidden pair (%d, %d)", match, i, j)
+		}
+		seen = append(seen, j)
+		count++
+		total += weights[i][j]
+	}
+
+	return count, total
+}
+
+// bruteForce returns the most rows from row on that can be matched to unused
+// columns, and the largest total weight of such a matching.
+func bruteForce(weights [][]float64, row int, used []bool) (int, float64) {
+	if row == len(weights) {
+		return 0, 0
+	}
+
+	bestCount, bestTotal := bruteForce(weights, row+1, used)
+	for j, w := range weights[row] {
+		if used[j] || w < 0 {
+			continue
+		}
+		used[j] = true
+		count, total := bruteForce(weights, row+1, used)
+		used[j] = false
+		count, total = count+1, total+w
+		if count > bestCount || (count == bestCount && total > bestTotal) {
+			bestCount, bestTotal = count, total
+		}
+	}
+
+	return bestCount, bestTotal
+}
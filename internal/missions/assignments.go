@@ -0,0 +1,119 @@
+package missions
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"spy-cat-agency/internal/models"
+	"spy-cat-agency/internal/storage"
+)
+
+var (
+	ErrCatBusy     = errors.New("Cat is already on an active mission")
+	ErrNotAssigned = errors.New("Mission has no cat assigned")
+)
+
+// Reasons an assignment ended.
+const (
+	ReasonUnassigned = "unassigned"
+	ReasonReassigned = "reassigned"
+	ReasonCompleted  = "completed"
+	ReasonAborted    = "aborted"
+)
+
+// oneActivePerCat is the unique index keeping a cat on one active mission at a time.
+const oneActivePerCat = "missions_one_active_per_cat_idx"
+
+// assignError turns a violation of the one active mission per cat rule into ErrCatBusy.
+func assignError(err error, catID int) error {
+	if storage.IsUniqueViolation(err, oneActivePerCat) {
+		return fmt.Errorf("%w: cat %d", ErrCatBusy, catID)
+	}
+	return err
+}
+
+// openAssignment records that a cat was assigned to a mission.
+func openAssignment(ctx context.Context, tx *sql.Tx, missionID, catID int) error {
+	query := `
+		INSERT INTO mission_assignments (mission_id, cat_id) VALUES ($1, $2)
+	`
+	_, err := tx.ExecContext(ctx, query, missionID, catID)
+	return err
+}
+
+// closeAssignment ends the open assignment of a mission, if any.
+func closeAssignment(ctx context.Context, tx *sql.Tx, missionID int, reason string) error {
+	query := `
+		UPDATE mission_assignments SET unassigned_at = NOW(), unassign_reason = $2
+		WHERE mission_id = $1 AND unassigned_at IS NULL
+	`
+	_, err := tx.ExecContext(ctx, query, missionID, reason)
+	return err
+}
+
+// Unassign takes the cat off an assigned mission, moving it back to draft.
+func (r *Repository) Unassign(ctx context.Context, missionID int) error {
+	return storage.WithTx(ctx, r.DB, nil, func(tx *sql.Tx) error {
+		missionQuery := `
+			SELECT status FROM missions WHERE id = $1 FOR UPDATE
+		`
+		var status models.MissionStatus
+		if err := tx.QueryRowContext(ctx, missionQuery, missionID).Scan(&status); err != nil {
+			return err
+		}
+		if status == models.MissionDraft {
+			return ErrNotAssigned
+		}
+		if !CanTransition(status, models.MissionDraft) {
+			return transitionError(status, models.MissionDraft)
+		}
+
+		updateQuery := `
+			UPDATE missions SET cat_id = NULL, status = $2 WHERE id = $1
+		`
+		if _, err := tx.ExecContext(ctx, updateQuery, missionID, models.MissionDraft); err != nil {
+			return err
+		}
+
+		return closeAssignment(ctx, tx, missionID, ReasonUnassigned)
+	})
+}
+
+// Assignments returns the assignment history of a mission, oldest first.
+func (r *Repository) Assignments(ctx context.Context, missionID int) ([]models.Assignment, error) {
+	var exists bool
+	existsQuery := `
+		SELECT EXISTS(SELECT 1 FROM missions WHERE id = $1)
+	`
+	if err := r.DB.QueryRowContext(ctx, existsQuery, missionID).Scan(&exists); err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, sql.ErrNoRows
+	}
+
+	query := `
+		SELECT id, mission_id, cat_id, assigned_at, unassigned_at, COALESCE(unassign_reason, '')
+		FROM mission_assignments
+		WHERE mission_id = $1
+		ORDER BY assigned_at, id
+	`
+	rows, err := r.DB.QueryContext(ctx, query, missionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	assignments := []models.Assignment{}
+	for rows.Next() {
+		var a models.Assignment
+		if err := rows.Scan(&a.ID, &a.MissionID, &a.CatID, &a.AssignedAt, &a.UnassignedAt, &a.UnassignReason); err != nil {
+			return nil, err
+		}
+		assignments = append(assignments, a)
+	}
+
+	return assignments, rows.Err()
+}
@@ -0,0 +1,60 @@
+package missions
+
+import (
+	"context"
+	"testing"
+
+	"spy-cat-agency/internal/models"
+	"spy-cat-agency/internal/storage/storagetest"
+)
+
+func TestFinishedMissionsCloseAssignments(t *testing.T) {
+	db := storagetest.Open(t)
+	repo := NewRepository(db)
+	ctx := context.Background()
+
+	tests := []struct {
+		name       string
+		finish     func(mission *models.Mission) error
+		wantReason string
+	}{
+		{
+			name: "completed",
+			finish: func(mission *models.Mission) error {
+				_, err := repo.CompleteTarget(ctx, mission.Targets[0].ID, DefaultPolicy())
+				return err
+			},
+			wantReason: ReasonCompleted,
+		},
+		{
+			name: "aborted",
+			finish: func(mission *models.Mission) error {
+				return NewService(repo, DefaultPolicy()).Abort(ctx, mission.ID)
+			},
+			wantReason: ReasonAborted,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mission := createTestMission(t, repo, 1)
+			if err := repo.AssignCat(ctx, mission.ID, createTestCat(t, db)); err != nil {
+				t.Fatal(err)
+			}
+			if err := tt.finish(mission); err != nil {
+				t.Fatal(err)
+			}
+
+			assignments, err := repo.Assignments(ctx, mission.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(assignments) != 1 {
+				t.Fatalf("got %d assignments, want 1", len(assignments))
+			}
+			if a := assignments[0]; a.UnassignedAt == nil || a.UnassignReason != tt.wantReason {
+				t.Errorf("assignment ended at %v because %q, want it ended because %q", a.UnassignedAt, a.UnassignReason, tt.wantReason)
+			}
+		})
+	}
+}
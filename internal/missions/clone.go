@@ -0,0 +1,102 @@
+package missions
+
+import (
+	"context"
+	"database/sql"
+
+	"spy-cat-agency/internal/models"
+	"spy-cat-agency/internal/storage"
+)
+
+// CloneOptions selects what is copied besides the targets when cloning a mission.
+type CloneOptions struct {
+	// KeepNotes copies the notes journals of the targets.
+	KeepNotes bool
+	// KeepCat assigns the clone to the same cat, which must be available.
+	KeepCat bool
+}
+
+// Clone creates a mission with the targets of another one, in any status, and
+// records where it was cloned from. The clone is a draft unless the cat is
+// kept, its targets are open and have no due dates.
+func (r *Repository) Clone(ctx context.Context, missionID int, opts CloneOptions, policy Policy) (*models.Mission, error) {
+	var clone *models.Mission
+	err := storage.WithTx(ctx, r.DB, nil, func(tx *sql.Tx) error {
+		// Keep the targets of the source mission from changing while they are copied
+		var catID sql.NullInt64
+		sourceQuery := `
+			SELECT cat_id FROM missions WHERE id = $1 FOR SHARE
+		`
+		if err := tx.QueryRowContext(ctx, sourceQuery, missionID).Scan(&catID); err != nil {
+			return err
+		}
+
+		mission := &models.Mission{SourceMissionID: missionID}
+		if opts.KeepCat {
+			mission.CatID = int(catID.Int64)
+		}
+
+		targets, err := cloneTargets(ctx, tx, missionID, opts.KeepNotes)
+		if err != nil {
+			return err
+		}
+		if err := policy.checkTargets(len(targets)); err != nil {
+			return err
+		}
+		mission.Targets = targets
+
+		clone, err = createMission(ctx, tx, mission)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return clone, nil
+}
+
+// cloneTargets reads the names and countries of a mission's targets, and their
+// notes if keepNotes is set, to be inserted into another mission.
+func cloneTargets(ctx context.Context, tx *sql.Tx, missionID int, keepNotes bool) ([]models.Target, error) {
+	query := `
+		SELECT id, name, country FROM targets
+		WHERE mission_id = $1
+		ORDER BY id
+	`
+	rows, err := tx.QueryContext(ctx, query, missionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var (
+		targets []models.Target
+		ids     []int64
+	)
+	byTarget := make(map[int]int)
+	for rows.Next() {
+		var t models.Target
+		if err := rows.Scan(&t.ID, &t.Name, &t.Country); err != nil {
+			return nil, err
+		}
+		byTarget[t.ID] = len(targets)
+		ids = append(ids, int64(t.ID))
+		targets = append(targets, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	if keepNotes && len(ids) > 0 {
+		err := queryNotes(ctx, tx, ids, func(note models.Note) {
+			t := &targets[byTarget[note.TargetID]]
+			t.Notes = append(t.Notes, models.Note{Author: note.Author, Body: note.Body})
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return targets, nil
+}
@@ -0,0 +1,152 @@
+package missions
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"spy-cat-agency/internal/models"
+	"spy-cat-agency/internal/storage/storagetest"
+)
+
+// workers is how many goroutines race each other in the tests below.
+const workers = 10
+
+// testDB opens a database for the tests below, with enough connections for
+// all workers to hold a transaction at once.
+func testDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	db := storagetest.Open(t)
+	db.SetMaxOpenConns(workers * 2)
+
+	return db
+}
+
+// race runs fn from workers goroutines at once and returns their errors.
+func race(fn func(i int) error) []error {
+	var (
+		wg    sync.WaitGroup
+		start = make(chan struct{})
+		errs  = make([]error, workers)
+	)
+	for i := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			errs[i] = fn(i)
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	return errs
+}
+
+func TestAddTargetsConcurrently(t *testing.T) {
+	db := testDB(t)
+	repo := NewRepository(db)
+	policy := DefaultPolicy()
+	mission := createTestMission(t, repo, 1)
+
+	errs := race(func(i int) error {
+		target := models.Target{Name: fmt.Sprintf("Extra %d", i), Country: "DE"}
+		_, err := repo.AddTargets(context.Background(), mission.ID, []models.Target{target}, policy)
+		return err
+	})
+
+	added := 0
+	for _, err := range errs {
+		switch {
+		case err == nil:
+			added++
+		case !errors.Is(err, ErrTooManyTargets):
+			t.Errorf("AddTargets: %v", err)
+		}
+	}
+	if want := policy.MaxTargets - 1; added != want {
+		t.Errorf("added %d targets, want %d", added, want)
+	}
+
+	var count int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM targets WHERE mission_id = $1`, mission.ID).Scan(&count); err != nil {
+		t.Fatal(err)
+	}
+	if count != policy.MaxTargets {
+		t.Errorf("mission has %d targets, want %d", count, policy.MaxTargets)
+	}
+}
+
+func TestAssignCatConcurrently(t *testing.T) {
+	db := testDB(t)
+	repo := NewRepository(db)
+	catID := createTestCat(t, db)
+
+	missionIDs := make([]int, workers)
+	for i := range missionIDs {
+		missionIDs[i] = createTestMission(t, repo, 1).ID
+	}
+
+	errs := race(func(i int) error {
+		return repo.AssignCat(context.Background(), missionIDs[i], catID)
+	})
+
+	assigned := 0
+	for _, err := range errs {
+		switch {
+		case err == nil:
+			assigned++
+		case !errors.Is(err, ErrCatBusy):
+			t.Errorf("AssignCat: %v", err)
+		}
+	}
+	if assigned != 1 {
+		t.Errorf("cat assigned to %d missions, want 1", assigned)
+	}
+
+	var active, open int
+	activeQuery := `
+		SELECT COUNT(*) FROM missions WHERE cat_id = $1 AND status IN ('assigned', 'in_progress')
+	`
+	if err := db.QueryRow(activeQuery, catID).Scan(&active); err != nil {
+		t.Fatal(err)
+	}
+	openQuery := `
+		SELECT COUNT(*) FROM mission_assignments WHERE cat_id = $1 AND unassigned_at IS NULL
+	`
+	if err := db.QueryRow(openQuery, catID).Scan(&open); err != nil {
+		t.Fatal(err)
+	}
+	if active != 1 || open != 1 {
+		t.Errorf("cat has %d active missions and %d open assignments, want 1 and 1", active, open)
+	}
+}
+
+func TestCompleteTargetConcurrently(t *testing.T) {
+	db := testDB(t)
+	repo := NewRepository(db)
+	mission := createTestMission(t, repo, 1)
+	targetID := mission.Targets[0].ID
+
+	errs := race(func(i int) error {
+		_, err := repo.CompleteTarget(context.Background(), targetID, DefaultPolicy())
+		return err
+	})
+
+	completed := 0
+	for _, err := range errs {
+		switch {
+		case err == nil:
+			completed++
+		case !errors.Is(err, ErrTargetCompleted):
+			t.Errorf("CompleteTarget: %v", err)
+		}
+	}
+	if completed != 1 {
+		t.Errorf("target completed %d times, want 1", completed)
+	}
+}
@@ -0,0 +1,119 @@
+package missions
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"spy-cat-agency/internal/models"
+	"spy-cat-agency/internal/paging"
+	"spy-cat-agency/internal/validator"
+
+	"github.com/lib/pq"
+)
+
+// Filter narrows down and orders the missions returned by List.
+type Filter struct {
+	CatID       int
+	Statuses    []models.MissionStatus
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	DueFrom     *time.Time
+	DueTo       *time.Time
+	// Overdue matches unfinished missions past their due date, or with an
+	// open target past its own.
+	Overdue bool
+	// Country matches missions with at least one target in the country,
+	// given as an ISO 3166-1 alpha-2 code.
+	Country string
+	// Sort is "created_at", or "-created_at" for newest first.
+	Sort    string
+	Cursor  string
+	Limit   int
+	Include Include
+}
+
+// Page is a single page of missions.
+type Page struct {
+	Missions   []models.Mission `json:"missions"`
+	Total      int              `json:"total"`
+	Limit      int              `json:"limit"`
+	NextCursor string           `json:"next_cursor,omitempty"`
+}
+
+// cursor is the position of the last mission of a page.
+type cursor struct {
+	Desc      bool      `json:"d"`
+	CreatedAt time.Time `json:"c"`
+	ID        int       `json:"id"`
+}
+
+func (f *Filter) Validate(v *validator.Validator) {
+	v.Check(f.Sort == "" || f.Sort == "created_at" || f.Sort == "-created_at", "sort", "must be created_at or -created_at")
+	v.Check(f.Limit >= 0 && f.Limit <= paging.MaxLimit, "limit", fmt.Sprintf("must be between 1 and %d", paging.MaxLimit))
+	for _, status := range f.Statuses {
+		v.Check(validStatus(status), "status", fmt.Sprintf("unknown status %q", status))
+	}
+	if f.CreatedFrom != nil && f.CreatedTo != nil {
+		v.Check(!f.CreatedFrom.After(*f.CreatedTo), "created_from", "can't be after created_to")
+	}
+	if f.DueFrom != nil && f.DueTo != nil {
+		v.Check(!f.DueFrom.After(*f.DueTo), "due_from", "can't be after due_to")
+	}
+	if f.Cursor != "" {
+		var c cursor
+		err := paging.DecodeCursor(f.Cursor, &c)
+		v.Check(err == nil && c.Desc == f.desc(), "cursor", paging.ErrInvalidCursor.Error())
+	}
+}
+
+func (f *Filter) desc() bool {
+	return f.Sort == "-created_at"
+}
+
+// where builds the WHERE clause for the filters, without the cursor.
+func (f *Filter) where() (string, []any) {
+	var (
+		conds []string
+		args  []any
+	)
+	add := func(cond string, arg any) {
+		args = append(args, arg)
+		conds = append(conds, fmt.Sprintf(cond, len(args)))
+	}
+
+	if f.CatID != 0 {
+		add("m.cat_id = $%d", f.CatID)
+	}
+	if len(f.Statuses) > 0 {
+		statuses := make([]string, 0, len(f.Statuses))
+		for _, s := range f.Statuses {
+			statuses = append(statuses, string(s))
+		}
+		add("m.status = ANY($%d)", pq.Array(statuses))
+	}
+	if f.CreatedFrom != nil {
+		add("m.created_at >= $%d", *f.CreatedFrom)
+	}
+	if f.CreatedTo != nil {
+		add("m.created_at <= $%d", *f.CreatedTo)
+	}
+	if f.DueFrom != nil {
+		add("m.due_at >= $%d", *f.DueFrom)
+	}
+	if f.DueTo != nil {
+		add("m.due_at <= $%d", *f.DueTo)
+	}
+	if f.Overdue {
+		conds = append(conds, overdueCondition)
+	}
+	if f.Country != "" {
+		add("EXISTS (SELECT 1 FROM targets t WHERE t.mission_id = m.id AND LOWER(t.country) = LOWER($%d))", f.Country)
+	}
+
+	if len(conds) == 0 {
+		return "", args
+	}
+
+	return "WHERE " + strings.Join(conds, " AND "), args
+}
@@ -0,0 +1,41 @@
+package missions
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+
+	"spy-cat-agency/internal/models"
+)
+
+func createTestCat(t *testing.T, db *sql.DB) int {
+	t.Helper()
+
+	var id int
+	query := `
+		INSERT INTO cats (name, years_of_experience, breed, salary)
+		VALUES ('Tom', 3, 'Siamese', 100) RETURNING id
+	`
+	if err := db.QueryRow(query).Scan(&id); err != nil {
+		t.Fatal(err)
+	}
+	return id
+}
+
+func createTestMission(t *testing.T, repo *Repository, targets int) *models.Mission {
+	t.Helper()
+
+	mission := &models.Mission{}
+	for i := range targets {
+		mission.Targets = append(mission.Targets, models.Target{
+			Name:    fmt.Sprintf("Target %d", i),
+			Country: "FR",
+		})
+	}
+	created, err := repo.Create(context.Background(), mission, DefaultPolicy())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return created
+}
@@ -3,6 +3,7 @@ package missions
 import (
 	"context"
 	"errors"
+	"time"
 
 	"spy-cat-agency/internal/models"
 )
@@ -11,40 +12,133 @@ var ErrInvalidID = errors.New("ID can't be 0")
 
 type Service struct {
 	Repo
+	Policy Policy
 }
 
 func (s *Service) Create(ctx context.Context, mission *models.Mission) (*models.Mission, error) {
-	return s.Repo.Create(ctx, mission)
+	return s.Repo.Create(ctx, mission, s.Policy)
+}
+
+// CreateFromTemplate creates a mission with the targets and default notes of a template version.
+func (s *Service) CreateFromTemplate(ctx context.Context, from FromTemplate) (*models.Mission, error) {
+	return s.Repo.CreateFromTemplate(ctx, from, s.Policy)
+}
+
+// Clone creates a mission with the targets of another one.
+func (s *Service) Clone(ctx context.Context, missionID int, opts CloneOptions) (*models.Mission, error) {
+	return s.Repo.Clone(ctx, missionID, opts, s.Policy)
+}
+
+// Recommend ranks the available cats for a mission, explaining their scores.
+func (s *Service) Recommend(ctx context.Context, missionID int, opts RecommendOptions) (*Recommendation, error) {
+	return s.Repo.Recommend(ctx, missionID, opts)
+}
+
+// AssignBulk assigns cats to many draft missions at once with an optimal one-to-one matching.
+func (s *Service) AssignBulk(ctx context.Context, opts BulkOptions) (*BulkResult, error) {
+	return s.Repo.AssignBulk(ctx, opts)
+}
+
+// CreateTemplate adds the first version of a template. Templates are held to
+// the same target limit as missions.
+func (s *Service) CreateTemplate(ctx context.Context, template *models.MissionTemplate) (*models.MissionTemplate, error) {
+	if err := s.Policy.checkTargets(len(template.Targets)); err != nil {
+		return nil, err
+	}
+	return s.Repo.CreateTemplate(ctx, template)
+}
+
+// UpdateTemplate adds a new version of a template.
+func (s *Service) UpdateTemplate(ctx context.Context, id int, targets []models.TemplateTarget) (*models.MissionTemplate, error) {
+	if err := s.Policy.checkTargets(len(targets)); err != nil {
+		return nil, err
+	}
+	return s.Repo.UpdateTemplate(ctx, id, targets)
 }
 
 func (s *Service) Delete(ctx context.Context, missionID int) error {
-	return s.Repo.Delete(ctx, missionID)
+	return s.Repo.Delete(ctx, missionID, s.Policy)
+}
+
+// Start moves an assigned mission into progress.
+func (s *Service) Start(ctx context.Context, missionID int) error {
+	return s.transition(ctx, missionID, models.MissionInProgress)
+}
+
+// Complete finishes a mission in progress, or a draft mission if the policy
+// allows completing unassigned missions, once all of its targets are completed.
+func (s *Service) Complete(ctx context.Context, missionID int) error {
+	return s.transition(ctx, missionID, models.MissionCompleted)
+}
+
+// Abort cancels a mission that isn't completed yet.
+func (s *Service) Abort(ctx context.Context, missionID int) error {
+	return s.transition(ctx, missionID, models.MissionAborted)
+}
+
+func (s *Service) transition(ctx context.Context, missionID int, to models.MissionStatus) error {
+	return s.Repo.Transition(ctx, missionID, to, s.Policy)
+}
+
+// AddNote appends an entry to a target's notes journal.
+func (s *Service) AddNote(ctx context.Context, targetID int, note models.Note) (*models.Note, error) {
+	return s.Repo.AddNote(ctx, targetID, note)
 }
 
-func (s *Service) UpdateAsCompleted(ctx context.Context, missionID int) error {
-	return s.Repo.UpdateAsCompleted(ctx, missionID)
+// Search ranks the targets and notes matching a full-text query.
+func (s *Service) Search(ctx context.Context, query SearchQuery) (*SearchPage, error) {
+	return s.Repo.Search(ctx, query)
 }
 
-func (s *Service) UpdateTargetNotes(ctx context.Context, targetID int, notes string) error {
-	return s.Repo.UpdateTargetNotes(ctx, targetID, notes)
+func (s *Service) Notes(ctx context.Context, targetID int) ([]models.Note, error) {
+	return s.Repo.Notes(ctx, targetID)
 }
 
 func (s *Service) DeleteTarget(ctx context.Context, targetID int) error {
 	return s.Repo.DeleteTarget(ctx, targetID)
 }
 
+// CompleteTarget marks a target as completed and reports whether
+// its mission was completed as well.
+func (s *Service) CompleteTarget(ctx context.Context, targetID int) (bool, error) {
+	return s.Repo.CompleteTarget(ctx, targetID, s.Policy)
+}
+
 func (s *Service) AddTargets(ctx context.Context, missionID int, targets []models.Target) ([]models.Target, error) {
-	return s.Repo.AddTargets(ctx, missionID, targets)
+	return s.Repo.AddTargets(ctx, missionID, targets, s.Policy)
 }
 
 func (s *Service) AssignCat(ctx context.Context, missionID int, catID int) error {
 	return s.Repo.AssignCat(ctx, missionID, catID)
 }
 
-func (s *Service) List(ctx context.Context) (*[]models.Mission, error) {
-	return s.Repo.List(ctx)
+// Unassign takes the cat off an assigned mission, moving it back to draft.
+func (s *Service) Unassign(ctx context.Context, missionID int) error {
+	return s.Repo.Unassign(ctx, missionID)
+}
+
+func (s *Service) Assignments(ctx context.Context, missionID int) ([]models.Assignment, error) {
+	return s.Repo.Assignments(ctx, missionID)
+}
+
+// SetDueAt sets or, with a nil dueAt, clears the due date of a mission.
+func (s *Service) SetDueAt(ctx context.Context, missionID int, dueAt *time.Time) error {
+	return s.Repo.SetDueAt(ctx, missionID, dueAt)
+}
+
+// SetTargetDueAt sets or, with a nil dueAt, clears the due date of a target.
+func (s *Service) SetTargetDueAt(ctx context.Context, targetID int, dueAt *time.Time) error {
+	return s.Repo.SetTargetDueAt(ctx, targetID, dueAt)
+}
+
+func (s *Service) OverdueEvents(ctx context.Context, missionID int) ([]models.OverdueEvent, error) {
+	return s.Repo.OverdueEvents(ctx, missionID)
+}
+
+func (s *Service) List(ctx context.Context, filter Filter) (*Page, error) {
+	return s.Repo.List(ctx, filter)
 }
 
-func (s *Service) Get(ctx context.Context, id int) (*models.Mission, error) {
-	return s.Repo.Get(ctx, id)
+func (s *Service) Get(ctx context.Context, id int, include Include) (*models.Mission, error) {
+	return s.Repo.Get(ctx, id, include)
 }
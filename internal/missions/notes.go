@@ -0,0 +1,135 @@
+package missions
+
+import (
+	"context"
+	"database/sql"
+
+	"spy-cat-agency/internal/models"
+	"spy-cat-agency/internal/storage"
+
+	"github.com/lib/pq"
+)
+
+// AddNote appends an entry to a target's notes journal. Notes can't be
+// added to completed targets or to targets of completed or aborted missions.
+func (r *Repository) AddNote(ctx context.Context, targetID int, note models.Note) (*models.Note, error) {
+	var added models.Note
+	err := storage.WithTx(ctx, r.DB, nil, func(tx *sql.Tx) error {
+		var missionID int
+		missionIDQuery := `
+			SELECT mission_id FROM targets WHERE id = $1
+		`
+		if err := tx.QueryRowContext(ctx, missionIDQuery, targetID).Scan(&missionID); err != nil {
+			return err
+		}
+
+		// Lock the parent mission before the target, in the same order as
+		// CompleteTarget, so a note can't land on a mission being completed
+		var status models.MissionStatus
+		missionStatusQuery := `
+			SELECT status FROM missions
+			WHERE id = $1
+			FOR SHARE
+		`
+		if err := tx.QueryRowContext(ctx, missionStatusQuery, missionID).Scan(&status); err != nil {
+			return err
+		}
+		if err := lockedError(status); err != nil {
+			return err
+		}
+
+		var isCompleted bool
+		targetQuery := `
+			SELECT is_completed FROM targets
+			WHERE id = $1
+			FOR SHARE
+		`
+		if err := tx.QueryRowContext(ctx, targetQuery, targetID).Scan(&isCompleted); err != nil {
+			return err
+		}
+		if isCompleted {
+			return ErrTargetCompleted
+		}
+
+		notes, err := insertNotes(ctx, tx, targetID, []models.Note{note})
+		if err != nil {
+			return err
+		}
+		added = notes[0]
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &added, nil
+}
+
+// Notes returns the notes journal of a target, oldest first.
+func (r *Repository) Notes(ctx context.Context, targetID int) ([]models.Note, error) {
+	var exists bool
+	existsQuery := `
+		SELECT EXISTS(SELECT 1 FROM targets WHERE id = $1)
+	`
+	if err := r.DB.QueryRowContext(ctx, existsQuery, targetID).Scan(&exists); err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, sql.ErrNoRows
+	}
+
+	notes := []models.Note{}
+	err := queryNotes(ctx, r.DB, []int64{int64(targetID)}, func(note models.Note) {
+		notes = append(notes, note)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return notes, nil
+}
+
+// insertNotes appends notes to a target's journal within a transaction.
+func insertNotes(ctx context.Context, tx *sql.Tx, targetID int, notes []models.Note) ([]models.Note, error) {
+	query := `
+		INSERT INTO target_notes (target_id, author, body)
+		VALUES ($1, $2, $3)
+		RETURNING id, created_at
+	`
+	inserted := make([]models.Note, 0, len(notes))
+	for _, note := range notes {
+		note.TargetID = targetID
+		if err := tx.QueryRowContext(ctx, query, targetID, note.Author, note.Body).Scan(&note.ID, &note.CreatedAt); err != nil {
+			return nil, err
+		}
+		inserted = append(inserted, note)
+	}
+
+	return inserted, nil
+}
+
+// queryNotes calls fn with the notes of the targets, oldest first.
+func queryNotes(ctx context.Context, q querier, targetIDs []int64, fn func(models.Note)) error {
+	query := `
+		SELECT id, target_id, author, body, created_at
+		FROM target_notes
+		WHERE target_id = ANY($1)
+		ORDER BY target_id, created_at, id
+	`
+	rows, err := q.QueryContext(ctx, query, pq.Array(targetIDs))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var note models.Note
+		if err := rows.Scan(&note.ID, &note.TargetID, &note.Author, &note.Body, &note.CreatedAt); err != nil {
+			return err
+		}
+		fn(note)
+	}
+
+	return rows.Err()
+}
@@ -0,0 +1,177 @@
+package missions
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"time"
+
+	"spy-cat-agency/internal/models"
+	"spy-cat-agency/internal/storage"
+)
+
+// overdueCondition matches unfinished missions past their due date or with
+// an open target past its own.
+const overdueCondition = `m.status NOT IN ('completed', 'aborted') AND (
+	m.due_at < NOW() OR EXISTS (
+		SELECT 1 FROM targets t
+		WHERE t.mission_id = m.id AND NOT t.is_completed AND t.due_at < NOW()
+	)
+)`
+
+// FlagOverdue marks the unfinished missions and open targets that went past
+// their due date since the last check, and records an event for each of them.
+// Missions and targets are only flagged once.
+func (r *Repository) FlagOverdue(ctx context.Context) ([]models.OverdueEvent, error) {
+	query := `
+		WITH overdue_missions AS (
+			UPDATE missions SET overdue_at = NOW()
+			WHERE overdue_at IS NULL AND due_at < NOW()
+				AND status NOT IN ('completed', 'aborted')
+			RETURNING id, due_at
+		),
+		overdue_targets AS (
+			UPDATE targets t SET overdue_at = NOW()
+			FROM missions m
+			WHERE m.id = t.mission_id AND t.overdue_at IS NULL AND t.due_at < NOW()
+				AND NOT t.is_completed AND m.status NOT IN ('completed', 'aborted')
+			RETURNING t.mission_id, t.id, t.due_at
+		)
+		INSERT INTO overdue_events (mission_id, target_id, due_at)
+		SELECT id, NULL::int, due_at FROM overdue_missions
+		UNION ALL
+		SELECT mission_id, id, due_at FROM overdue_targets
+		RETURNING id, mission_id, COALESCE(target_id, 0), due_at, detected_at
+	`
+	rows, err := r.DB.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := []models.OverdueEvent{}
+	for rows.Next() {
+		var event models.OverdueEvent
+		if err := rows.Scan(&event.ID, &event.MissionID, &event.TargetID, &event.DueAt, &event.DetectedAt); err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
+
+// SetDueAt sets or, with a nil dueAt, clears the due date of a mission that
+// hasn't ended. The mission can be flagged overdue again against its new due date.
+func (r *Repository) SetDueAt(ctx context.Context, missionID int, dueAt *time.Time) error {
+	return storage.WithTx(ctx, r.DB, nil, func(tx *sql.Tx) error {
+		statusQuery := `
+			SELECT status FROM missions WHERE id = $1 FOR UPDATE
+		`
+		var status models.MissionStatus
+		if err := tx.QueryRowContext(ctx, statusQuery, missionID).Scan(&status); err != nil {
+			return err
+		}
+		if err := lockedError(status); err != nil {
+			return err
+		}
+
+		updateQuery := `
+			UPDATE missions SET due_at = $2, overdue_at = NULL WHERE id = $1
+		`
+		_, err := tx.ExecContext(ctx, updateQuery, missionID, dueAt)
+		return err
+	})
+}
+
+// SetTargetDueAt sets or, with a nil dueAt, clears the due date of an open
+// target. The target can be flagged overdue again against its new due date.
+func (r *Repository) SetTargetDueAt(ctx context.Context, targetID int, dueAt *time.Time) error {
+	return storage.WithTx(ctx, r.DB, nil, func(tx *sql.Tx) error {
+		status, isCompleted, err := lockTarget(ctx, tx, targetID)
+		if err != nil {
+			return err
+		}
+		if isCompleted {
+			return ErrTargetCompleted
+		}
+		if err := lockedError(status); err != nil {
+			return err
+		}
+
+		updateQuery := `
+			UPDATE targets SET due_at = $2, overdue_at = NULL WHERE id = $1
+		`
+		_, err = tx.ExecContext(ctx, updateQuery, targetID, dueAt)
+		return err
+	})
+}
+
+// OverdueEvents returns the missed deadlines of a mission and its targets, oldest first.
+func (r *Repository) OverdueEvents(ctx context.Context, missionID int) ([]models.OverdueEvent, error) {
+	var exists bool
+	existsQuery := `
+		SELECT EXISTS(SELECT 1 FROM missions WHERE id = $1)
+	`
+	if err := r.DB.QueryRowContext(ctx, existsQuery, missionID).Scan(&exists); err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, sql.ErrNoRows
+	}
+
+	query := `
+		SELECT id, mission_id, COALESCE(target_id, 0), due_at, detected_at
+		FROM overdue_events
+		WHERE mission_id = $1
+		ORDER BY detected_at, id
+	`
+	rows, err := r.DB.QueryContext(ctx, query, missionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := []models.OverdueEvent{}
+	for rows.Next() {
+		var event models.OverdueEvent
+		if err := rows.Scan(&event.ID, &event.MissionID, &event.TargetID, &event.DueAt, &event.DetectedAt); err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
+
+// WatchOverdue flags overdue missions and targets every interval until ctx is done.
+func (s *Service) WatchOverdue(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		s.flagOverdue(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Service) flagOverdue(ctx context.Context) {
+	events, err := s.Repo.FlagOverdue(ctx)
+	if err != nil {
+		slog.Error("Overdue check", "error", err)
+		return
+	}
+
+	for _, event := range events {
+		if event.TargetID != 0 {
+			slog.Warn("Target overdue", "mission id", event.MissionID, "target id", event.TargetID, "due at", event.DueAt)
+			continue
+		}
+		slog.Warn("Mission overdue", "mission id", event.MissionID, "due at", event.DueAt)
+	}
+}
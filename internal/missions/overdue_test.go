@@ -0,0 +1,61 @@
+package missions
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"spy-cat-agency/internal/storage/storagetest"
+)
+
+func TestSetDueAtFlagsMissionAgain(t *testing.T) {
+	db := storagetest.Open(t)
+	repo := NewRepository(db)
+	ctx := context.Background()
+	mission := createTestMission(t, repo, 1)
+
+	// Due dates set through the API are in the future, let this one lapse
+	if _, err := db.Exec(`UPDATE missions SET due_at = NOW() - INTERVAL '1 hour' WHERE id = $1`, mission.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.FlagOverdue(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	dueAt := time.Now().UTC().Add(time.Hour)
+	if err := repo.SetDueAt(ctx, mission.ID, &dueAt); err != nil {
+		t.Fatal(err)
+	}
+	got, err := repo.Get(ctx, mission.ID, Include{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.OverdueAt != nil || got.DueAt == nil || !got.DueAt.Equal(dueAt.Truncate(time.Microsecond)) {
+		t.Errorf("mission due at %v, overdue at %v, want due at %v and not overdue", got.DueAt, got.OverdueAt, dueAt)
+	}
+
+	// The missed deadline stays in the events, and a lapsed new one is flagged again
+	if _, err := db.Exec(`UPDATE missions SET due_at = NOW() - INTERVAL '1 minute' WHERE id = $1`, mission.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.FlagOverdue(ctx); err != nil {
+		t.Fatal(err)
+	}
+	events, err := repo.OverdueEvents(ctx, mission.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 2 {
+		t.Errorf("got %d overdue events, want 2", len(events))
+	}
+
+	if err := repo.SetDueAt(ctx, mission.ID, nil); err != nil {
+		t.Fatal(err)
+	}
+	if got, err = repo.Get(ctx, mission.ID, Include{}); err != nil {
+		t.Fatal(err)
+	}
+	if got.DueAt != nil || got.OverdueAt != nil {
+		t.Errorf("mission due at %v, overdue at %v, want no due date", got.DueAt, got.OverdueAt)
+	}
+}
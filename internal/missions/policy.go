@@ -0,0 +1,61 @@
+package missions
+
+import (
+	"errors"
+	"fmt"
+
+	"spy-cat-agency/internal/models"
+)
+
+var (
+	ErrMissionAssigned = errors.New("Mission is assigned to a cat, unable to delete")
+	ErrInvalidPolicy   = errors.New("invalid missions policy")
+)
+
+// Policy holds the configurable rules every mission write is checked against.
+type Policy struct {
+	// MaxTargets is the most targets a mission can have.
+	MaxTargets int `json:"max_targets"`
+	// AllowDeleteAssigned allows deleting missions that have a cat.
+	AllowDeleteAssigned bool `json:"allow_delete_assigned"`
+	// AllowCompleteUnassigned allows completing draft missions, which have
+	// no cat, once all of their targets are completed.
+	AllowCompleteUnassigned bool `json:"allow_complete_unassigned"`
+}
+
+func DefaultPolicy() Policy {
+	return Policy{
+		MaxTargets: 3,
+	}
+}
+
+func (p Policy) Validate() error {
+	if p.MaxTargets < 1 {
+		return fmt.Errorf("%w: max targets must be at least 1", ErrInvalidPolicy)
+	}
+	return nil
+}
+
+// checkTargets fails with ErrTooManyTargets if a mission would end up with more targets than allowed.
+func (p Policy) checkTargets(count int) error {
+	if count > p.MaxTargets {
+		return fmt.Errorf("%w: a mission can have at most %d", ErrTooManyTargets, p.MaxTargets)
+	}
+	return nil
+}
+
+func (p Policy) canTransition(from, to models.MissionStatus) bool {
+	if p.AllowCompleteUnassigned && from == models.MissionDraft && to == models.MissionCompleted {
+		return true
+	}
+	return CanTransition(from, to)
+}
+
+// completableStatuses are the statuses a mission is completed from once its last target is done.
+func (p Policy) completableStatuses() []string {
+	statuses := []string{string(models.MissionInProgress)}
+	if p.AllowCompleteUnassigned {
+		statuses = append(statuses, string(models.MissionDraft))
+	}
+	return statuses
+}
@@ -0,0 +1,464 @@
+package missions
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	"spy-cat-agency/internal/matching"
+	"spy-cat-agency/internal/models"
+	"spy-cat-agency/internal/paging"
+	"spy-cat-agency/internal/storage"
+
+	"github.com/lib/pq"
+)
+
+var (
+	ErrNoDraftMissions = errors.New("No draft missions to assign")
+	ErrNotDrafts       = errors.New("Missions aren't drafts")
+)
+
+// Points of every score factor. Past success in the mission's countries
+// weighs the most, the budget only counts when one is given.
+const (
+	experiencePoints = 30
+	successPoints    = 40
+	breedPoints      = 10
+	budgetPoints     = 20
+
+	// Experience and success past these counts earn no more points.
+	experienceCap   = 10
+	successCap      = 5
+	breedSuccessCap = 10
+)
+
+// RecommendOptions narrows down the cats recommended for missions.
+type RecommendOptions struct {
+	// Budget leaves out cats paid in other currencies or paid more, and
+	// favours cheaper ones.
+	Budget   *models.Money `json:"budget,omitempty" swaggertype:"number"`
+	Currency string        `json:"currency,omitempty"`
+	Limit    int           `json:"-"`
+}
+
+// Factor is the part of a candidate's score earned for one criterion.
+type Factor struct {
+	Name      string  `json:"factor"`
+	Points    float64 `json:"points"`
+	MaxPoints float64 `json:"max_points"`
+	Reason    string  `json:"reason"`
+}
+
+// Candidate is a cat ranked for a mission, with an explanation of its score.
+type Candidate struct {
+	Cat     models.Cat `json:"cat"`
+	Score   float64    `json:"score"`
+	Factors []Factor   `json:"factors"`
+}
+
+// Recommendation ranks the available cats for a mission, best first.
+type Recommendation struct {
+	MissionID  int         `json:"mission_id"`
+	Countries  []string    `json:"countries"`
+	Candidates []Candidate `json:"candidates"`
+}
+
+// BulkOptions selects the draft missions assigned at once.
+type BulkOptions struct {
+	RecommendOptions
+	// MissionIDs are the draft missions to assign, or the oldest ones if
+	// empty. AssignBulk fails with ErrNotDrafts if any of them isn't a draft.
+	MissionIDs []int `json:"mission_ids,omitempty"`
+	// DryRun returns the matching without assigning the cats.
+	DryRun bool `json:"dry_run,omitempty"`
+}
+
+// BulkAssignment is a cat matched to a mission by AssignBulk.
+type BulkAssignment struct {
+	MissionID int       `json:"mission_id"`
+	Candidate Candidate `json:"candidate"`
+}
+
+// BulkResult is the matching of cats to missions with the largest total score.
+type BulkResult struct {
+	Assignments []BulkAssignment `json:"assignments"`
+	// Unmatched are the missions left without a cat.
+	Unmatched  []int   `json:"unmatched"`
+	TotalScore float64 `json:"total_score"`
+	DryRun     bool    `json:"dry_run"`
+}
+
+// candidateStats is what a candidate's score is computed from.
+type candidateStats struct {
+	cat              models.Cat
+	completedTargets int
+	missions         int
+	breedTargets     int
+}
+
+// Recommend ranks the available cats for a mission that can still take a cat,
+// by experience, past success in the mission's target countries, the success
+// of their breed there, and salary if a budget is given.
+func (r *Repository) Recommend(ctx context.Context, missionID int, opts RecommendOptions) (*Recommendation, error) {
+	var status models.MissionStatus
+	err := r.DB.QueryRowContext(ctx, `SELECT status FROM missions WHERE id = $1`, missionID).Scan(&status)
+	if err != nil {
+		return nil, err
+	}
+	if !canAssign(status) {
+		return nil, transitionError(status, models.MissionAssigned)
+	}
+
+	recommendation, err := recommend(ctx, r.DB, missionID, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := paging.Limit(opts.Limit)
+	if len(recommendation.Candidates) > limit {
+		recommendation.Candidates = recommendation.Candidates[:limit]
+	}
+
+	return recommendation, nil
+}
+
+// AssignBulk assigns available cats to draft missions in one transaction,
+// using the one-to-one matching with the largest total score. Missions are
+// left unmatched when there aren't enough cats within the budget.
+func (r *Repository) AssignBulk(ctx context.Context, opts BulkOptions) (*BulkResult, error) {
+	var result *BulkResult
+	err := storage.WithTx(ctx, r.DB, nil, func(tx *sql.Tx) error {
+		missionIDs, err := lockDrafts(ctx, tx, opts.MissionIDs)
+		if err != nil {
+			return err
+		}
+		if len(missionIDs) == 0 {
+			return ErrNoDraftMissions
+		}
+
+		// Score every available cat for every mission
+		var (
+			recommendations = make([]*Recommendation, len(missionIDs))
+			catIDs          []int64
+			catColumn       = make(map[int64]int)
+		)
+		for i, missionID := range missionIDs {
+			recommendations[i], err = recommend(ctx, tx, missionID, opts.RecommendOptions)
+			if err != nil {
+				return err
+			}
+			for _, candidate := range recommendations[i].Candidates {
+				if _, ok := catColumn[candidate.Cat.ID]; !ok {
+					catColumn[candidate.Cat.ID] = len(catIDs)
+					catIDs = append(catIDs, candidate.Cat.ID)
+				}
+			}
+		}
+
+		weights := make([][]float64, len(missionIDs))
+		candidates := make([]map[int]Candidate, len(missionIDs))
+		for i, recommendation := range recommendations {
+			weights[i] = make([]float64, len(catIDs))
+			for j := range weights[i] {
+				weights[i][j] = -1
+			}
+			candidates[i] = make(map[int]Candidate, len(recommendation.Candidates))
+			for _, candidate := range recommendation.Candidates {
+				j := catColumn[candidate.Cat.ID]
+				weights[i][j] = candidate.Score
+				candidates[i][j] = candidate
+			}
+		}
+
+		result = &BulkResult{
+			Assignments: []BulkAssignment{},
+			Unmatched:   []int{},
+			DryRun:      opts.DryRun,
+		}
+		match := []int{}
+		if len(catIDs) > 0 {
+			match = matching.MaxWeight(weights)
+		}
+		for i, missionID := range missionIDs {
+			if len(match) == 0 || match[i] < 0 {
+				result.Unmatched = append(result.Unmatched, missionID)
+				continue
+			}
+			candidate := candidates[i][match[i]]
+			result.Assignments = append(result.Assignments, BulkAssignment{MissionID: missionID, Candidate: candidate})
+			result.TotalScore += candidate.Score
+		}
+		result.TotalScore = round(result.TotalScore)
+
+		if opts.DryRun {
+			return nil
+		}
+
+		for _, assignment := range result.Assignments {
+			if err := assignDraft(ctx, tx, assignment.MissionID, int(assignment.Candidate.Cat.ID)); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// lockDrafts locks the draft missions with the given ids, or the oldest draft
+// missions if ids is empty, and returns their IDs. It fails with ErrNotDrafts
+// listing the ids of missions that don't exist or aren't drafts.
+func lockDrafts(ctx context.Context, tx *sql.Tx, ids []int) ([]int, error) {
+	query := `
+		SELECT id FROM missions
+		WHERE status = $1 AND (cardinality($2::int[]) = 0 OR id = ANY($2))
+		ORDER BY id
+		LIMIT $3
+		FOR UPDATE
+	`
+	rows, err := tx.QueryContext(ctx, query, models.MissionDraft, pq.Array(ids), paging.MaxLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var drafts []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		drafts = append(drafts, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var notDrafts []int
+	for _, id := range ids {
+		if !slices.Contains(drafts, id) && !slices.Contains(notDrafts, id) {
+			notDrafts = append(notDrafts, id)
+		}
+	}
+	if len(notDrafts) > 0 {
+		return nil, fmt.Errorf("%w: %v", ErrNotDrafts, notDrafts)
+	}
+
+	return drafts, nil
+}
+
+// assignDraft assigns an available cat to a locked draft mission.
+func assignDraft(ctx context.Context, tx *sql.Tx, missionID, catID int) error {
+	if err := checkCat(ctx, tx, catID, missionID); err != nil {
+		return err
+	}
+
+	updateQuery := `
+		UPDATE missions SET cat_id = $1, status = $2
+		WHERE id = $3
+	`
+	if _, err := tx.ExecContext(ctx, updateQuery, catID, models.MissionAssigned, missionID); err != nil {
+		return assignError(err, catID)
+	}
+
+	return openAssignment(ctx, tx, missionID, catID)
+}
+
+// recommend scores the available cats for a mission, best first.
+func recommend(ctx context.Context, q querier, missionID int, opts RecommendOptions) (*Recommendation, error) {
+	countries, err := missionCountries(ctx, q, missionID)
+	if err != nil {
+		return nil, err
+	}
+
+	stats, err := candidatesStats(ctx, q, missionID, countries)
+	if err != nil {
+		return nil, err
+	}
+
+	recommendation := &Recommendation{
+		MissionID:  missionID,
+		Countries:  countries,
+		Candidates: []Candidate{},
+	}
+	for _, s := range stats {
+		if !opts.withinBudget(s.cat) {
+			continue
+		}
+		recommendation.Candidates = append(recommendation.Candidates, score(s, countries, opts))
+	}
+
+	// Best score first, then the cheapest
+	slices.SortFunc(recommendation.Candidates, func(a, b Candidate) int {
+		return cmp.Or(
+			cmp.Compare(b.Score, a.Score),
+			cmp.Compare(a.Cat.Salary, b.Cat.Salary),
+			cmp.Compare(a.Cat.ID, b.Cat.ID),
+		)
+	})
+
+	return recommendation, nil
+}
+
+func missionCountries(ctx context.Context, q querier, missionID int) ([]string, error) {
+	query := `
+		SELECT DISTINCT country FROM targets
+		WHERE mission_id = $1
+		ORDER BY country
+	`
+	rows, err := q.QueryContext(ctx, query, missionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	countries := []string{}
+	for rows.Next() {
+		var country string
+		if err := rows.Scan(&country); err != nil {
+			return nil, err
+		}
+		countries = append(countries, country)
+	}
+
+	return countries, rows.Err()
+}
+
+// candidatesStats reads the available cats, on duty and without an active
+// mission other than missionID, with the targets they and their breed
+// completed in the countries.
+func candidatesStats(ctx context.Context, q querier, missionID int, countries []string) ([]candidateStats, error) {
+	query := `
+		WITH success AS (
+			SELECT ms.cat_id, COUNT(*) AS targets, COUNT(DISTINCT ms.id) AS missions
+			FROM targets t
+			JOIN missions ms ON ms.id = t.mission_id
+			WHERE t.is_completed AND ms.status = 'completed' AND ms.cat_id IS NOT NULL
+				AND LOWER(t.country) = ANY(SELECT LOWER(unnest($2::text[])))
+			GROUP BY ms.cat_id
+		),
+		breed_success AS (
+			SELECT c.breed, SUM(s.targets) AS targets
+			FROM success s
+			JOIN cats c ON c.id = s.cat_id
+			GROUP BY c.breed
+		)
+		SELECT c.id, c.name, c.breed, c.years_of_experience, c.salary, c.currency,
+			COALESCE(s.targets, 0), COALESCE(s.missions, 0), COALESCE(bs.targets, 0)
+		FROM cats c
+		LEFT JOIN success s ON s.cat_id = c.id
+		LEFT JOIN breed_success bs ON bs.breed = c.breed
+		WHERE c.deleted_at IS NULL AND c.duty_status = 'active'
+			AND NOT EXISTS (
+				SELECT 1 FROM missions m
+				WHERE m.cat_id = c.id AND m.id <> $1 AND m.status IN ('assigned', 'in_progress')
+			)
+		ORDER BY c.id
+	`
+	rows, err := q.QueryContext(ctx, query, missionID, pq.Array(countries))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var stats []candidateStats
+	for rows.Next() {
+		var s candidateStats
+		if err := rows.Scan(
+			&s.cat.ID,
+			&s.cat.Name,
+			&s.cat.Breed,
+			&s.cat.YearsOfExperience,
+			&s.cat.Salary,
+			&s.cat.Currency,
+			&s.completedTargets,
+			&s.missions,
+			&s.breedTargets,
+		); err != nil {
+			return nil, err
+		}
+		stats = append(stats, s)
+	}
+
+	return stats, rows.Err()
+}
+
+// score adds up the points a cat earns for a mission and explains them.
+func score(s candidateStats, countries []string, opts RecommendOptions) Candidate {
+	candidate := Candidate{Cat: s.cat}
+	add := func(name string, points, maxPoints float64, reason string) {
+		points = round(points)
+		candidate.Score += points
+		candidate.Factors = append(candidate.Factors, Factor{
+			Name:      name,
+			Points:    points,
+			MaxPoints: maxPoints,
+			Reason:    reason,
+		})
+	}
+
+	add("experience",
+		experiencePoints*capped(int(s.cat.YearsOfExperience), experienceCap),
+		experiencePoints,
+		fmt.Sprintf("%d years of experience", s.cat.YearsOfExperience))
+
+	where := strings.Join(countries, ", ")
+	switch {
+	case len(countries) == 0:
+		add("success", 0, successPoints, "The mission has no targets yet")
+	case s.completedTargets == 0:
+		add("success", 0, successPoints, fmt.Sprintf("No targets completed in %s", where))
+	default:
+		add("success",
+			successPoints*capped(s.completedTargets, successCap),
+			successPoints,
+			fmt.Sprintf("Completed %d targets over %d missions in %s", s.completedTargets, s.missions, where))
+	}
+
+	if len(countries) > 0 {
+		add("breed",
+			breedPoints*capped(s.breedTargets, breedSuccessCap),
+			breedPoints,
+			fmt.Sprintf("%s cats completed %d targets in %s", s.cat.Breed, s.breedTargets, where))
+	}
+
+	if opts.Budget != nil {
+		switch {
+		case *opts.Budget == 0:
+			add("budget", budgetPoints, budgetPoints, "Unpaid, within the budget")
+		default:
+			saved := 1 - float64(s.cat.Salary)/float64(*opts.Budget)
+			add("budget", budgetPoints*saved, budgetPoints,
+				fmt.Sprintf("Salary of %s %s is %.0f%% under the %s %s budget",
+					s.cat.Salary, s.cat.Currency, saved*100, opts.Budget, opts.Currency))
+		}
+	}
+
+	candidate.Score = round(candidate.Score)
+
+	return candidate
+}
+
+// withinBudget reports whether a cat is paid no more than the budget, in its
+// currency. Salaries in other currencies can't be compared and are left out.
+func (opts RecommendOptions) withinBudget(cat models.Cat) bool {
+	return opts.Budget == nil || (cat.Currency == opts.Currency && cat.Salary <= *opts.Budget)
+}
+
+// capped returns n as a fraction of limit, at most 1.
+func capped(n, limit int) float64 {
+	return float64(min(n, limit)) / float64(limit)
+}
+
+func round(f float64) float64 {
+	return math.Round(f*100) / 100
+}
@@ -0,0 +1,119 @@
+package missions
+
+import (
+	"testing"
+
+	"spy-cat-agency/internal/models"
+)
+
+func TestScore(t *testing.T) {
+	budget := models.Money(1000_00)
+	tests := []struct {
+		name      string
+		stats     candidateStats
+		countries []string
+		opts      RecommendOptions
+		want      float64
+		factors   map[string]float64
+	}{
+		{
+			name:  "no targets yet",
+			stats: candidateStats{cat: models.Cat{YearsOfExperience: 5}},
+			want:  15,
+			factors: map[string]float64{
+				"experience": 15,
+				"success":    0,
+			},
+		},
+		{
+			name: "capped experience and success",
+			stats: candidateStats{
+				cat:              models.Cat{YearsOfExperience: 20},
+				completedTargets: 8,
+				missions:         3,
+				breedTargets:     30,
+			},
+			countries: []string{"FR"},
+			want:      80,
+			factors: map[string]float64{
+				"experience": 30,
+				"success":    40,
+				"breed":      10,
+			},
+		},
+		{
+			name: "partial success",
+			stats: candidateStats{
+				cat:              models.Cat{YearsOfExperience: 1},
+				completedTargets: 2,
+				missions:         1,
+				breedTargets:     5,
+			},
+			countries: []string{"DE", "FR"},
+			want:      24,
+			factors: map[string]float64{
+				"experience": 3,
+				"success":    16,
+				"breed":      5,
+			},
+		},
+		{
+			name:  "salary under the budget",
+			stats: candidateStats{cat: models.Cat{Salary: 250_00, Currency: "USD"}},
+			opts:  RecommendOptions{Budget: &budget, Currency: "USD"},
+			want:  15,
+			factors: map[string]float64{
+				"experience": 0,
+				"success":    0,
+				"budget":     15,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := score(tt.stats, tt.countries, tt.opts)
+			if got.Score != tt.want {
+				t.Errorf("score = %v, want %v", got.Score, tt.want)
+			}
+			if len(got.Factors) != len(tt.factors) {
+				t.Errorf("got %d factors, want %d", len(got.Factors), len(tt.factors))
+			}
+			for _, factor := range got.Factors {
+				want, ok := tt.factors[factor.Name]
+				if !ok {
+					t.Errorf("unexpected factor %q", factor.Name)
+					continue
+				}
+				if factor.Points != want {
+					t.Errorf("%s factor = %v points, want %v", factor.Name, factor.Points, want)
+				}
+				if factor.Points > factor.MaxPoints || factor.Reason == "" {
+					t.Errorf("%s factor = %+v, want at most its max points and a reason", factor.Name, factor)
+				}
+			}
+		})
+	}
+}
+
+func TestWithinBudget(t *testing.T) {
+	budget := models.Money(1000_00)
+	tests := []struct {
+		name string
+		cat  models.Cat
+		opts RecommendOptions
+		want bool
+	}{
+		{name: "no budget", cat: models.Cat{Salary: 5000_00, Currency: "JPY"}, want: true},
+		{name: "under", cat: models.Cat{Salary: 999_99, Currency: "USD"}, opts: RecommendOptions{Budget: &budget, Currency: "USD"}, want: true},
+		{name: "equal", cat: models.Cat{Salary: 1000_00, Currency: "USD"}, opts: RecommendOptions{Budget: &budget, Currency: "USD"}, want: true},
+		{name: "over", cat: models.Cat{Salary: 1000_01, Currency: "USD"}, opts: RecommendOptions{Budget: &budget, Currency: "USD"}, want: false},
+		{name: "other currency", cat: models.Cat{Salary: 10_00, Currency: "JPY"}, opts: RecommendOptions{Budget: &budget, Currency: "USD"}, want: false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.opts.withinBudget(tt.cat); got != tt.want {
+			t.Errorf("%s: withinBudget = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package missions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"spy-cat-agency/internal/models"
+
+	"github.com/lib/pq"
+)
+
+var ErrUnknownInclude = errors.New("Unknown include option")
+
+// Include selects the related records embedded in mission reads.
+// Targets are always loaded.
+type Include struct {
+	Cat bool
+}
+
+// ParseInclude parses a comma separated list of relations, e.g. "cat".
+func ParseInclude(value string) (Include, error) {
+	var include Include
+	for _, name := range strings.Split(value, ",") {
+		switch strings.TrimSpace(name) {
+		case "":
+		case "cat":
+			include.Cat = true
+		default:
+			return Include{}, fmt.Errorf("%w: %q", ErrUnknownInclude, name)
+		}
+	}
+
+	return include, nil
+}
+
+// loadRelations fills targets with their notes, and the assigned cats if requested, for
+// all the missions using one query per relation.
+func (r *Repository) loadRelations(ctx context.Context, missions []models.Mission, include Include) error {
+	if len(missions) == 0 {
+		return nil
+	}
+
+	if err := r.loadTargets(ctx, missions); err != nil {
+		return err
+	}
+
+	if include.Cat {
+		return r.loadCats(ctx, missions)
+	}
+
+	return nil
+}
+
+func (r *Repository) loadTargets(ctx context.Context, missions []models.Mission) error {
+	ids := make([]int64, 0, len(missions))
+	byMission := make(map[int]int, len(missions))
+	for i, m := range missions {
+		ids = append(ids, int64(m.ID))
+		byMission[m.ID] = i
+	}
+
+	query := `
+		SELECT id, mission_id, name, country, country_name, is_completed, due_at, overdue_at
+		FROM targets
+		WHERE mission_id = ANY($1)
+		ORDER BY mission_id, id
+	`
+	rows, err := r.DB.QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var t models.Target
+		if err := rows.Scan(&t.ID, &t.MissionID, &t.Name, &t.Country, &t.CountryName, &t.IsCompleted, &t.DueAt, &t.OverdueAt); err != nil {
+			return err
+		}
+		m := &missions[byMission[t.MissionID]]
+		m.Targets = append(m.Targets, t)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	return r.loadNotes(ctx, missions)
+}
+
+// loadNotes fills the notes journals of the targets of all the missions.
+func (r *Repository) loadNotes(ctx context.Context, missions []models.Mission) error {
+	var ids []int64
+	byTarget := make(map[int]*models.Target)
+	for i := range missions {
+		for j := range missions[i].Targets {
+			t := &missions[i].Targets[j]
+			ids = append(ids, int64(t.ID))
+			byTarget[t.ID] = t
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	return queryNotes(ctx, r.DB, ids, func(note models.Note) {
+		t := byTarget[note.TargetID]
+		t.Notes = append(t.Notes, note)
+	})
+}
+
+func (r *Repository) loadCats(ctx context.Context, missions []models.Mission) error {
+	ids := make([]int64, 0, len(missions))
+	for _, m := range missions {
+		if m.CatID != 0 {
+			ids = append(ids, int64(m.CatID))
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	query := `
+		SELECT id, name, years_of_experience, breed, salary, currency
+		FROM cats
+		WHERE id = ANY($1)
+	`
+	rows, err := r.DB.QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	cats := make(map[int]*models.Cat, len(ids))
+	for rows.Next() {
+		var cat models.Cat
+		if err := rows.Scan(&cat.ID, &cat.Name, &cat.YearsOfExperience, &cat.Breed, &cat.Salary, &cat.Currency); err != nil {
+			return err
+		}
+		cats[int(cat.ID)] = &cat
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for i := range missions {
+		missions[i].Cat = cats[missions[i].CatID]
+	}
+
+	return nil
+}
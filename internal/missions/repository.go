@@ -4,30 +4,57 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"log/slog"
 	"time"
 
+	"spy-cat-agency/internal/countries"
 	"spy-cat-agency/internal/models"
+	"spy-cat-agency/internal/paging"
+	"spy-cat-agency/internal/storage"
+
+	"github.com/lib/pq"
 )
 
 type Repo interface {
-	AddTargets(ctx context.Context, missionID int, newTargets []models.Target) ([]models.Target, error)
+	AddTargets(ctx context.Context, missionID int, newTargets []models.Target, policy Policy) ([]models.Target, error)
 	AssignCat(ctx context.Context, missionID int, catID int) error
-	Create(ctx context.Context, mission *models.Mission) (*models.Mission, error)
-	Delete(ctx context.Context, missionID int) error
+	AssignBulk(ctx context.Context, opts BulkOptions) (*BulkResult, error)
+	Recommend(ctx context.Context, missionID int, opts RecommendOptions) (*Recommendation, error)
+	CompleteTarget(ctx context.Context, targetID int, policy Policy) (bool, error)
+	Create(ctx context.Context, mission *models.Mission, policy Policy) (*models.Mission, error)
+	CreateFromTemplate(ctx context.Context, from FromTemplate, policy Policy) (*models.Mission, error)
+	Clone(ctx context.Context, missionID int, opts CloneOptions, policy Policy) (*models.Mission, error)
+	Delete(ctx context.Context, missionID int, policy Policy) error
 	DeleteTarget(ctx context.Context, targetID int) error
-	Get(ctx context.Context, id int) (*models.Mission, error)
-	List(ctx context.Context) (*[]models.Mission, error)
-	UpdateAsCompleted(ctx context.Context, missionID int) error
-	UpdateTargetNotes(ctx context.Context, targetID int, newNotes string) error
+	FlagOverdue(ctx context.Context) ([]models.OverdueEvent, error)
+	SetDueAt(ctx context.Context, missionID int, dueAt *time.Time) error
+	SetTargetDueAt(ctx context.Context, targetID int, dueAt *time.Time) error
+	OverdueEvents(ctx context.Context, missionID int) ([]models.OverdueEvent, error)
+	Get(ctx context.Context, id int, include Include) (*models.Mission, error)
+	List(ctx context.Context, filter Filter) (*Page, error)
+	Search(ctx context.Context, query SearchQuery) (*SearchPage, error)
+	Transition(ctx context.Context, missionID int, to models.MissionStatus, policy Policy) error
+	AddNote(ctx context.Context, targetID int, note models.Note) (*models.Note, error)
+	Notes(ctx context.Context, targetID int) ([]models.Note, error)
+	Unassign(ctx context.Context, missionID int) error
+	Assignments(ctx context.Context, missionID int) ([]models.Assignment, error)
+	CreateTemplate(ctx context.Context, template *models.MissionTemplate) (*models.MissionTemplate, error)
+	UpdateTemplate(ctx context.Context, id int, targets []models.TemplateTarget) (*models.MissionTemplate, error)
+	DeleteTemplate(ctx context.Context, id int) error
+	GetTemplate(ctx context.Context, id int) (*models.MissionTemplate, error)
+	ListTemplates(ctx context.Context) ([]models.MissionTemplate, error)
+	TemplateVersions(ctx context.Context, id int) ([]models.MissionTemplate, error)
 }
 
 var (
 	ErrTargetCompleted  = errors.New("Target is completed, unable to edit")
 	ErrMIssionCompleted = errors.New("Mission is completed, unable to edit")
+	ErrMissionAborted   = errors.New("Mission is aborted, unable to edit")
 	ErrMissionNotFound  = errors.New("Mission not found")
 	ErrTooManyTargets   = errors.New("Too many targets")
 	ErrCatNotFound      = errors.New("Cat not found")
+	ErrCatUnavailable   = errors.New("Cat isn't available")
 )
 
 type Repository struct {
@@ -40,36 +67,85 @@ func NewRepository(db *sql.DB) *Repository {
 	}
 }
 
-func (r *Repository) Create(ctx context.Context, mission *models.Mission) (*models.Mission, error) {
+// Create inserts a mission with its targets, as many as the policy allows.
+func (r *Repository) Create(ctx context.Context, mission *models.Mission, policy Policy) (*models.Mission, error) {
 	tx, err := r.DB.BeginTx(ctx, nil)
 	if err != nil {
 		return nil, err
 	}
 	defer tx.Rollback()
 
+	if err := policy.checkTargets(len(mission.Targets)); err != nil {
+		return nil, err
+	}
+
+	newMission, err := createMission(ctx, tx, mission)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return newMission, nil
+}
+
+// createMission inserts a mission with its targets and their notes, and opens
+// the cat's assignment, within a transaction.
+func createMission(ctx context.Context, tx *sql.Tx, mission *models.Mission) (*models.Mission, error) {
 	// Insert mission
 	insertMissionQuery := `
-		INSERT INTO missions (cat_id, is_completed, created_at)
-    	VALUES ($1, false, NOW()) RETURNING id
+		INSERT INTO missions (cat_id, status, due_at, template_id, source_mission_id, created_at)
+    	VALUES ($1, $2, $3, $4, $5, NOW()) RETURNING id, created_at
 	`
 
-	var missionID int
-	err = tx.QueryRow(insertMissionQuery,
-		mission.CatID).Scan(&missionID)
+	status := models.MissionDraft
+	if mission.CatID != 0 {
+		status = models.MissionAssigned
+		if err := checkCat(ctx, tx, mission.CatID, 0); err != nil {
+			return nil, err
+		}
+	}
+
+	var catID *int
+	if mission.CatID != 0 {
+		catID = &mission.CatID
+	}
+
+	var templateID, sourceMissionID *int
+	if mission.TemplateID != 0 {
+		templateID = &mission.TemplateID
+	}
+	if mission.SourceMissionID != 0 {
+		sourceMissionID = &mission.SourceMissionID
+	}
+
+	var (
+		missionID int
+		createdAt time.Time
+	)
+	err := tx.QueryRowContext(ctx, insertMissionQuery,
+		catID, status, mission.DueAt, templateID, sourceMissionID).Scan(&missionID, &createdAt)
 	if err != nil {
 		slog.Error("Mission insert", "query row", err)
-		tx.Rollback()
-		return nil, err
+		return nil, assignError(err, mission.CatID)
+	}
+
+	if catID != nil {
+		if err := openAssignment(ctx, tx, missionID, *catID); err != nil {
+			return nil, err
+		}
 	}
 
 	// Insert targets
 	inserTargetsQuery := `
-		INSERT INTO targets (mission_id, name, country, notes, is_completed)
-        VALUES ($1, $2, $3, $4, false)
+		INSERT INTO targets (mission_id, name, country, country_name, due_at, is_completed)
+        VALUES ($1, $2, $3, $4, $5, false)
         RETURNING id
 	`
 	targets := make([]models.Target, 0, len(mission.Targets))
-	stmt, err := tx.Prepare(inserTargetsQuery)
+	stmt, err := tx.PrepareContext(ctx, inserTargetsQuery)
 	if err != nil {
 		return nil, err
 	}
@@ -77,52 +153,59 @@ func (r *Repository) Create(ctx context.Context, mission *models.Mission) (*mode
 
 	for _, t := range mission.Targets {
 		var targetID int
-		if err := stmt.QueryRow(missionID, t.Name, t.Country, t.Notes).Scan(&targetID); err != nil {
+		if err := stmt.QueryRowContext(ctx, missionID, t.Name, t.Country, countries.Name(t.Country), t.DueAt).Scan(&targetID); err != nil {
 			slog.Error("Inserting target", "error", err)
 			return nil, err
 		}
+		notes, err := insertNotes(ctx, tx, targetID, t.Notes)
+		if err != nil {
+			slog.Error("Inserting target notes", "error", err)
+			return nil, err
+		}
 		targets = append(targets, models.Target{
 			ID:          targetID,
+			MissionID:   missionID,
 			Name:        t.Name,
 			Country:     t.Country,
-			Notes:       t.Notes,
+			CountryName: countries.Name(t.Country),
+			Notes:       notes,
 			IsCompleted: false,
+			DueAt:       t.DueAt,
 		})
 	}
 
-	if err := tx.Commit(); err != nil {
-		return nil, err
-	}
-
 	newMission := &models.Mission{
-		ID:          missionID,
-		CatID:       mission.CatID,
-		IsCompleted: false,
-		CreatedAt:   time.Time{},
-		Targets:     targets,
+		ID:              missionID,
+		CatID:           mission.CatID,
+		Status:          status,
+		CreatedAt:       createdAt,
+		DueAt:           mission.DueAt,
+		TemplateID:      mission.TemplateID,
+		SourceMissionID: mission.SourceMissionID,
+		Targets:         targets,
 	}
 
 	return newMission, nil
 }
 
-func (r *Repository) Delete(ctx context.Context, missionID int) error {
+func (r *Repository) Delete(ctx context.Context, missionID int, policy Policy) error {
 	tx, err := r.DB.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
 	defer tx.Rollback()
 
-	// Check if mission is assigned to a cat
+	// Check if mission is assigned to a cat, locking it so a cat can't be assigned meanwhile
 	var catID sql.NullInt64
 	err = tx.QueryRow(`
-        SELECT cat_id FROM missions WHERE id = $1
+        SELECT cat_id FROM missions WHERE id = $1 FOR UPDATE
     `, missionID).Scan(&catID)
 	if err != nil {
 		return err
 	}
 
-	if catID.Valid {
-		return errors.New("cannot delete a mission assigned to a cat")
+	if catID.Valid && !policy.AllowDeleteAssigned {
+		return ErrMissionAssigned
 	}
 
 	// Delete the mission (targets will be deleted automatically via ON DELETE CASCADE)
@@ -141,295 +224,464 @@ func (r *Repository) Delete(ctx context.Context, missionID int) error {
 	return nil
 }
 
-func (r *Repository) UpdateAsCompleted(ctx context.Context, missionID int) error {
-	tx, err := r.DB.BeginTx(ctx, nil)
-	if err != nil {
-		return err
-	}
-	defer tx.Rollback()
+// Transition moves a mission to another status allowed by the policy. The
+// mission row is locked while the transition is checked, so targets can't be
+// added or completed in between. Completing a mission fails with
+// ErrOpenTargets until all of its targets are completed. A completed or
+// aborted mission ends the assignment of its cat.
+func (r *Repository) Transition(ctx context.Context, missionID int, to models.MissionStatus, policy Policy) error {
+	return storage.WithTx(ctx, r.DB, nil, func(tx *sql.Tx) error {
+		statusQuery := `
+			SELECT status FROM missions WHERE id = $1 FOR UPDATE
+		`
+		var from models.MissionStatus
+		if err := tx.QueryRowContext(ctx, statusQuery, missionID).Scan(&from); err != nil {
+			return err
+		}
 
-	// Check if mission exists
-	existsQuery := `
-		SELECT EXISTS(SELECT 1 FROM missions WHERE id = $1)
-	`
-	var exists bool
-	err = tx.QueryRow(existsQuery, missionID).Scan(&exists)
-	if err != nil {
-		return err
-	}
-	if !exists {
-		return sql.ErrNoRows
-	}
+		if !policy.canTransition(from, to) {
+			return transitionError(from, to)
+		}
 
-	// Mark the mission as completed
-	updateQuery := `
-        UPDATE missions SET is_completed = TRUE
-        WHERE id = $1
-    `
-	_, err = tx.Exec(updateQuery, missionID)
-	if err != nil {
-		return err
-	}
+		if to == models.MissionCompleted {
+			openTargetsQuery := `
+				SELECT COUNT(*) FROM targets WHERE mission_id = $1 AND is_completed = FALSE
+			`
+			var open int
+			if err := tx.QueryRowContext(ctx, openTargetsQuery, missionID).Scan(&open); err != nil {
+				return err
+			}
+			if open > 0 {
+				return ErrOpenTargets
+			}
+		}
 
-	if err := tx.Commit(); err != nil {
-		return err
-	}
+		updateQuery := `
+			UPDATE missions SET status = $1,
+				completed_at = CASE WHEN $3 THEN NOW() ELSE completed_at END
+			WHERE id = $2
+		`
+		_, err := tx.ExecContext(ctx, updateQuery, to, missionID, to == models.MissionCompleted)
+		if err != nil {
+			return err
+		}
 
-	return nil
+		switch to {
+		case models.MissionCompleted:
+			return closeAssignment(ctx, tx, missionID, ReasonCompleted)
+		case models.MissionAborted:
+			return closeAssignment(ctx, tx, missionID, ReasonAborted)
+		default:
+			return nil
+		}
+	})
 }
 
-func (r *Repository) UpdateTargetNotes(ctx context.Context, targetID int, newNotes string) error {
-	tx, err := r.DB.BeginTx(ctx, nil)
-	if err != nil {
-		return err
-	}
-	defer tx.Rollback()
+// DeleteTarget deletes a target that isn't completed. The parent mission is
+// locked before the target is checked, so a target being completed
+// concurrently can't be deleted.
+func (r *Repository) DeleteTarget(ctx context.Context, targetID int) error {
+	return storage.WithTx(ctx, r.DB, nil, func(tx *sql.Tx) error {
+		// Lock the parent mission so its target count doesn't change under a transition
+		_, isCompleted, err := lockTarget(ctx, tx, targetID)
+		if err != nil {
+			return err
+		}
+		if isCompleted {
+			return ErrTargetCompleted
+		}
 
-	// Check if target exists and is not completed
-	targetExistsQuery := `
-        SELECT is_completed, mission_id FROM targets
-        WHERE id = $1
-    `
-	var isTargetCompleted bool
-	var missionID int
-	err = tx.QueryRow(targetExistsQuery, targetID).Scan(&isTargetCompleted, &missionID)
-	if err != nil {
+		// Delete the target
+		deleteTargetQuery := `
+			DELETE FROM targets WHERE id = $1
+		`
+		_, err = tx.ExecContext(ctx, deleteTargetQuery, targetID)
 		return err
-	}
+	})
+}
 
-	if isTargetCompleted {
-		return ErrTargetCompleted
-	}
+// CompleteTarget marks a target as completed and reports whether that
+// completed its mission as well.
+func (r *Repository) CompleteTarget(ctx context.Context, targetID int, policy Policy) (bool, error) {
+	var missionCompleted bool
+	err := storage.WithTx(ctx, r.DB, nil, func(tx *sql.Tx) error {
+		// Lock the parent mission, it may be completed along with the target
+		status, isTargetCompleted, err := lockTarget(ctx, tx, targetID)
+		if err != nil {
+			return err
+		}
+		if isTargetCompleted {
+			return ErrTargetCompleted
+		}
+		if err := lockedError(status); err != nil {
+			return err
+		}
 
-	// Check if parent mission is not completed
-	missionNotCompletedQuery := `
-        SELECT is_completed FROM missions
-        WHERE id = $1
-    `
-	var isMissionCompleted bool
-	err = tx.QueryRow(missionNotCompletedQuery, missionID).Scan(&isMissionCompleted)
-	if err != nil {
-		return err
-	}
+		// Mark the target as completed
+		completeTargetQuery := `
+			UPDATE targets SET is_completed = TRUE, completed_at = NOW()
+			WHERE id = $1
+			RETURNING mission_id
+		`
+		var missionID int
+		if err := tx.QueryRowContext(ctx, completeTargetQuery, targetID).Scan(&missionID); err != nil {
+			return err
+		}
 
-	if isMissionCompleted {
-		return ErrMIssionCompleted
-	}
+		// Completing a target starts the work on an assigned mission
+		if status == models.MissionAssigned {
+			startMissionQuery := `
+				UPDATE missions SET status = $2 WHERE id = $1
+			`
+			if _, err := tx.ExecContext(ctx, startMissionQuery, missionID, models.MissionInProgress); err != nil {
+				return err
+			}
+		}
 
-	//  Update notes
-	updateNotesQuery := `
-        UPDATE targets SET notes = $1
-        WHERE id = $2
-    `
-	_, err = tx.Exec(updateNotesQuery, newNotes, targetID)
-	if err != nil {
-		return err
-	}
+		// Complete the mission once its last target is done
+		completeMissionQuery := `
+			UPDATE missions SET status = $2, completed_at = NOW()
+			WHERE id = $1 AND status = ANY($3) AND NOT EXISTS (
+				SELECT 1 FROM targets WHERE mission_id = $1 AND is_completed = FALSE
+			)
+		`
+		result, err := tx.ExecContext(ctx, completeMissionQuery, missionID, models.MissionCompleted, pq.Array(policy.completableStatuses()))
+		if err != nil {
+			return err
+		}
 
-	return tx.Commit()
-}
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		missionCompleted = rowsAffected > 0
 
-func (r *Repository) DeleteTarget(ctx context.Context, targetID int) error {
-	tx, err := r.DB.BeginTx(ctx, nil)
+		if missionCompleted {
+			return closeAssignment(ctx, tx, missionID, ReasonCompleted)
+		}
+		return nil
+	})
 	if err != nil {
-		return err
+		return false, err
 	}
-	defer tx.Rollback()
 
-	// Check if target exists and is not completed
-	targetExistsQuery := `
-        SELECT is_completed, mission_id FROM targets
-        WHERE id = $1
-    `
-	var isCompleted bool
-	var missionID int
-	err = tx.QueryRow(targetExistsQuery, targetID).Scan(&isCompleted, &missionID)
-	if err != nil {
-		return err
-	}
+	return missionCompleted, nil
+}
 
-	if isCompleted {
-		return ErrTargetCompleted
+// lockTarget locks a target's parent mission and then the target itself, and
+// returns the mission status and whether the target is completed. The
+// target is read again once the mission is locked, as it may have been
+// completed or deleted in the meantime.
+func lockTarget(ctx context.Context, tx *sql.Tx, targetID int) (models.MissionStatus, bool, error) {
+	var missionID int
+	missionIDQuery := `
+		SELECT mission_id FROM targets WHERE id = $1
+	`
+	if err := tx.QueryRowContext(ctx, missionIDQuery, targetID).Scan(&missionID); err != nil {
+		return "", false, err
 	}
 
-	// Optionally, check if mission exists
-	missionExistsQuery := `
-		SELECT EXISTS(SELECT 1 FROM missions WHERE id = $1)
+	var status models.MissionStatus
+	missionStatusQuery := `
+		SELECT status FROM missions WHERE id = $1 FOR UPDATE
 	`
-	var missionExists bool
-	err = tx.QueryRow(missionExistsQuery, missionID).Scan(&missionExists)
-	if err != nil {
-		return err
-	}
-	if !missionExists {
-		return ErrMissionNotFound
+	if err := tx.QueryRowContext(ctx, missionStatusQuery, missionID).Scan(&status); err != nil {
+		return "", false, err
 	}
 
-	// Delete the target
-	deleteTargetQuery := `
-		DELETE FROM targets WHERE id = $1
+	var isCompleted bool
+	targetQuery := `
+		SELECT is_completed FROM targets WHERE id = $1 FOR UPDATE
 	`
-	_, err = tx.Exec(deleteTargetQuery, targetID)
-	if err != nil {
-		return err
+	if err := tx.QueryRowContext(ctx, targetQuery, targetID).Scan(&isCompleted); err != nil {
+		return "", false, err
 	}
 
-	return tx.Commit()
+	return status, isCompleted, nil
 }
 
-func (r *Repository) AddTargets(ctx context.Context, missionID int, newTargets []models.Target) ([]models.Target, error) {
-	tx, err := r.DB.BeginTx(ctx, nil)
-	if err != nil {
-		return nil, err
-	}
-	defer tx.Rollback()
-
-	// Check mission exists and is not completed
-	var isMissionCompleted bool
-	missionQuery := `
-		SELECT is_completed FROM missions WHERE id = $1
-	`
-	err = tx.QueryRow(missionQuery, missionID).Scan(&isMissionCompleted)
-	if err != nil {
-		return nil, err
-	}
-
-	if isMissionCompleted {
-		return nil, ErrMIssionCompleted
-	}
+// AddTargets appends targets to a mission. The mission row stays locked
+// until the targets are inserted, so concurrent calls can't push the
+// mission past the target limit of the policy.
+func (r *Repository) AddTargets(ctx context.Context, missionID int, newTargets []models.Target, policy Policy) ([]models.Target, error) {
+	var insertedTargets []models.Target
+	err := storage.WithTx(ctx, r.DB, nil, func(tx *sql.Tx) error {
+		// Check mission exists and is not completed
+		var status models.MissionStatus
+		missionQuery := `
+			SELECT status FROM missions WHERE id = $1 FOR UPDATE
+		`
+		err := tx.QueryRowContext(ctx, missionQuery, missionID).Scan(&status)
+		if err != nil {
+			return err
+		}
 
-	// Count existing targets
-	var currentCount int
-	countQuery := `
-		SELECT COUNT(*) FROM targets WHERE mission_id = $1
-	`
-	err = tx.QueryRow(countQuery, missionID).Scan(&currentCount)
-	if err != nil {
-		return nil, err
-	}
+		if err := lockedError(status); err != nil {
+			return err
+		}
 
-	if currentCount+len(newTargets) > 3 {
-		return nil, ErrTooManyTargets
-	}
+		// Count existing targets
+		var currentCount int
+		countQuery := `
+			SELECT COUNT(*) FROM targets WHERE mission_id = $1
+		`
+		err = tx.QueryRowContext(ctx, countQuery, missionID).Scan(&currentCount)
+		if err != nil {
+			return err
+		}
 
-	// Insert new targets
-	insertQuery := `
-        INSERT INTO targets (mission_id, name, country, notes, is_completed)
-        VALUES ($1, $2, $3, $4, false)
-        RETURNING id
-    `
-	stmt, err := tx.Prepare(insertQuery)
-	if err != nil {
-		return nil, err
-	}
-	defer stmt.Close()
+		if err := policy.checkTargets(currentCount + len(newTargets)); err != nil {
+			return err
+		}
 
-	insertedTargets := make([]models.Target, 0, len(newTargets))
-	for _, t := range newTargets {
-		var targetID int
-		if err := stmt.QueryRow(missionID, t.Name, t.Country, t.Notes).Scan(&targetID); err != nil {
-			return nil, err
+		// Insert new targets
+		insertQuery := `
+			INSERT INTO targets (mission_id, name, country, country_name, due_at, is_completed)
+			VALUES ($1, $2, $3, $4, $5, false)
+			RETURNING id
+		`
+		stmt, err := tx.PrepareContext(ctx, insertQuery)
+		if err != nil {
+			return err
+		}
+		defer stmt.Close()
+
+		insertedTargets = make([]models.Target, 0, len(newTargets))
+		for _, t := range newTargets {
+			var targetID int
+			if err := stmt.QueryRowContext(ctx, missionID, t.Name, t.Country, countries.Name(t.Country), t.DueAt).Scan(&targetID); err != nil {
+				return err
+			}
+			notes, err := insertNotes(ctx, tx, targetID, t.Notes)
+			if err != nil {
+				return err
+			}
+			insertedTargets = append(insertedTargets, models.Target{
+				ID:          targetID,
+				MissionID:   missionID,
+				Name:        t.Name,
+				Country:     t.Country,
+				CountryName: countries.Name(t.Country),
+				Notes:       notes,
+				IsCompleted: false,
+				DueAt:       t.DueAt,
+			})
 		}
-		insertedTargets = append(insertedTargets, models.Target{
-			ID:          targetID,
-			MissionID:   missionID,
-			Name:        t.Name,
-			Country:     t.Country,
-			Notes:       t.Notes,
-			IsCompleted: false,
-		})
-	}
 
-	if err := tx.Commit(); err != nil {
+		return nil
+	})
+	if err != nil {
 		return nil, err
 	}
 
 	return insertedTargets, nil
 }
 
+// AssignCat assigns a cat to a mission. Both the mission and the cat rows
+// are locked, and the one active mission per cat index backs up the check
+// against a cat being assigned to two missions at once.
 func (r *Repository) AssignCat(ctx context.Context, missionID int, catID int) error {
-	tx, err := r.DB.BeginTx(ctx, nil)
-	if err != nil {
-		return err
-	}
-	defer tx.Rollback()
+	return storage.WithTx(ctx, r.DB, nil, func(tx *sql.Tx) error {
+		// Check mission exists and still accepts a cat
+		missionStatusQuery := `
+			SELECT status, cat_id FROM missions WHERE id = $1 FOR UPDATE
+		`
+		var status models.MissionStatus
+		var currentCatID sql.NullInt64
+		err := tx.QueryRowContext(ctx, missionStatusQuery, missionID).Scan(&status, &currentCatID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrMissionNotFound
+			}
+			return err
+		}
+		if !canAssign(status) {
+			return transitionError(status, models.MissionAssigned)
+		}
+		if currentCatID.Valid && int(currentCatID.Int64) == catID {
+			return nil
+		}
+
+		if err := checkCat(ctx, tx, catID, missionID); err != nil {
+			return err
+		}
+
+		// Assign cat to mission
+		updateQuery := `
+			UPDATE missions SET cat_id = $1, status = $2
+			WHERE id = $3
+		`
+		_, err = tx.ExecContext(ctx, updateQuery, catID, models.MissionAssigned, missionID)
+		if err != nil {
+			return assignError(err, catID)
+		}
 
-	// Check mission exists
-	missionExistsQuery := `
-		SELECT EXISTS(SELECT 1 FROM missions WHERE id = $1)
+		// Record the handover in the assignment history
+		if currentCatID.Valid {
+			if err := closeAssignment(ctx, tx, missionID, ReasonReassigned); err != nil {
+				return err
+			}
+		}
+		return openAssignment(ctx, tx, missionID, catID)
+	})
+}
+
+// checkCat locks a cat and makes sure it exists, wasn't removed and is available:
+// on duty and not on an active mission other than missionID, else it fails with ErrCatBusy.
+func checkCat(ctx context.Context, tx *sql.Tx, catID int, missionID int) error {
+	catQuery := `
+		SELECT c.duty_status, (
+			SELECT m.id FROM missions m
+			WHERE m.cat_id = c.id AND m.id <> $2 AND m.status IN ('assigned', 'in_progress')
+			LIMIT 1
+		)
+		FROM cats c
+		WHERE c.id = $1 AND c.deleted_at IS NULL
+		FOR UPDATE OF c
 	`
-	var exists bool
-	err = tx.QueryRow(missionExistsQuery, missionID).Scan(&exists)
+	var duty models.DutyStatus
+	var activeMissionID sql.NullInt64
+	err := tx.QueryRowContext(ctx, catQuery, catID, missionID).Scan(&duty, &activeMissionID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrCatNotFound
+	}
 	if err != nil {
 		return err
 	}
-	if !exists {
-		return ErrMissionNotFound
+	if duty != models.DutyActive {
+		return fmt.Errorf("%w: cat %d is %s", ErrCatUnavailable, catID, duty)
 	}
-
-	// Check cat exists
-	catExistsQuery := `
-		SELECT EXISTS(SELECT 1 FROM cats WHERE id = $1)
-	`
-	err = tx.QueryRow(catExistsQuery, catID).Scan(&exists)
-	if err != nil {
-		return err
+	if activeMissionID.Valid {
+		return fmt.Errorf("%w: cat %d is on mission %d", ErrCatBusy, catID, activeMissionID.Int64)
 	}
-	if !exists {
-		return ErrCatNotFound
+
+	return nil
+}
+
+func (r *Repository) List(ctx context.Context, filter Filter) (*Page, error) {
+	where, args := filter.where()
+
+	limit := paging.Limit(filter.Limit)
+	page := &Page{Missions: []models.Mission{}, Limit: limit}
+	countQuery := `SELECT COUNT(*) FROM missions m ` + where
+	if err := r.DB.QueryRowContext(ctx, countQuery, args...).Scan(&page.Total); err != nil {
+		return nil, err
 	}
 
-	// Assign cat to mission
-	updateQuery := `
-        UPDATE missions SET cat_id = $1
-        WHERE id = $2
-    `
-	_, err = tx.Exec(updateQuery, catID, missionID)
-	if err != nil {
-		return err
+	direction, cmp := "ASC", ">"
+	if filter.desc() {
+		direction, cmp = "DESC", "<"
 	}
 
-	return tx.Commit()
-}
+	if filter.Cursor != "" {
+		var c cursor
+		if err := paging.DecodeCursor(filter.Cursor, &c); err != nil {
+			return nil, err
+		}
+		args = append(args, c.CreatedAt, c.ID)
+		keyset := fmt.Sprintf("(m.created_at, m.id) %s ($%d, $%d)", cmp, len(args)-1, len(args))
+		if where == "" {
+			where = "WHERE " + keyset
+		} else {
+			where += " AND " + keyset
+		}
+	}
 
-func (r *Repository) List(ctx context.Context) (*[]models.Mission, error) {
-	query := `
-		SELECT id, cat_id, is_completed, created_at FROM missions
-	`
+	args = append(args, limit+1)
+	query := fmt.Sprintf(`
+		SELECT %s FROM missions m
+		%s
+		ORDER BY m.created_at %s, m.id %s
+		LIMIT $%d
+	`, missionColumns, where, direction, direction, len(args))
 
-	rows, err := r.DB.QueryContext(ctx, query)
+	rows, err := r.DB.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var missions []models.Mission
 	for rows.Next() {
-		var mission models.Mission
-		if err := rows.Scan(&mission.ID, &mission.CatID, &mission.IsCompleted, &mission.CreatedAt); err != nil {
+		mission, err := scanMission(rows)
+		if err != nil {
 			return nil, err
 		}
-		missions = append(missions, mission)
-
+		page.Missions = append(page.Missions, mission)
 	}
 
 	if err = rows.Err(); err != nil {
 		return nil, err
 	}
-	return &missions, nil
+
+	if len(page.Missions) > limit {
+		page.Missions = page.Missions[:limit]
+		last := page.Missions[limit-1]
+		page.NextCursor = paging.EncodeCursor(cursor{
+			Desc:      filter.desc(),
+			CreatedAt: last.CreatedAt,
+			ID:        last.ID,
+		})
+	}
+
+	if err := r.loadRelations(ctx, page.Missions, filter.Include); err != nil {
+		return nil, err
+	}
+
+	return page, nil
 }
 
-func (r *Repository) Get(ctx context.Context, id int) (*models.Mission, error) {
+func (r *Repository) Get(ctx context.Context, id int, include Include) (*models.Mission, error) {
 	query := `
-		SELECT id, cat_id, is_completed, created_at FROM missions
-		WHERE id = $1
+		SELECT ` + missionColumns + ` FROM missions m
+		WHERE m.id = $1
 	`
 
-	var mission models.Mission
-	err := r.DB.QueryRowContext(ctx, query, id).Scan(&mission.ID, &mission.CatID, &mission.IsCompleted, &mission.CreatedAt)
+	mission, err := scanMission(r.DB.QueryRowContext(ctx, query, id))
 	if err != nil {
 		return nil, err
 	}
 
-	return &mission, nil
+	missions := []models.Mission{mission}
+	if err := r.loadRelations(ctx, missions, include); err != nil {
+		return nil, err
+	}
+
+	return &missions[0], nil
+}
+
+// missionColumns are the columns read by scanMission, from missions aliased as m.
+const missionColumns = `m.id, m.cat_id, m.status, m.created_at, m.due_at, m.overdue_at, m.template_id, m.source_mission_id`
+
+// querier is implemented by both *sql.DB and *sql.Tx.
+type querier interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+// scanner is implemented by both *sql.Row and *sql.Rows.
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanMission(row scanner) (models.Mission, error) {
+	var (
+		mission         models.Mission
+		catID           sql.NullInt64
+		templateID      sql.NullInt64
+		sourceMissionID sql.NullInt64
+	)
+	err := row.Scan(
+		&mission.ID,
+		&catID,
+		&mission.Status,
+		&mission.CreatedAt,
+		&mission.DueAt,
+		&mission.OverdueAt,
+		&templateID,
+		&sourceMissionID,
+	)
+	mission.CatID = int(catID.Int64)
+	mission.TemplateID = int(templateID.Int64)
+	mission.SourceMissionID = int(sourceMissionID.Int64)
+
+	return mission, err
 }
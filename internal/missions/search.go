@@ -0,0 +1,212 @@
+package missions
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"spy-cat-agency/internal/models"
+	"spy-cat-agency/internal/paging"
+	"spy-cat-agency/internal/validator"
+
+	"github.com/lib/pq"
+)
+
+// Kinds of search hits.
+const (
+	HitTarget = "target"
+	HitNote   = "note"
+)
+
+// headlineOptions marks the matched words in snippets.
+const headlineOptions = "StartSel=<mark>, StopSel=</mark>, MaxWords=20, MinWords=5, MaxFragments=2"
+
+// escapedDoc is the text of a hit with its HTML special characters escaped,
+// so the only markup in a snippet is the <mark> tags added by ts_headline.
+// The escapes are parsed as entities, which don't break up the words around them.
+const escapedDoc = `replace(replace(replace(doc, '&', '&amp;'), '<', '&lt;'), '>', '&gt;')`
+
+// SearchQuery is a full-text search over target names, countries and notes.
+type SearchQuery struct {
+	// Text is a web search style query, e.g. `"safe house" -berlin`.
+	Text     string
+	CatID    int
+	Statuses []models.MissionStatus
+	// ExcludeCompleted leaves out completed targets and their notes.
+	ExcludeCompleted bool
+	Cursor           string
+	Limit            int
+}
+
+// SearchHit is a target or a note matching a search, with a link to its mission.
+type SearchHit struct {
+	Kind          string               `json:"kind"`
+	MissionID     int                  `json:"mission_id"`
+	MissionStatus models.MissionStatus `json:"mission_status"`
+	MissionURL    string               `json:"mission_url"`
+	TargetID      int                  `json:"target_id"`
+	NoteID        int                  `json:"note_id,omitempty"`
+	Rank          float32              `json:"rank"`
+	// Snippet is the matched text, HTML escaped, with the matches wrapped in <mark> tags.
+	Snippet string `json:"snippet"`
+}
+
+// SearchPage is a single page of search hits, best matches first.
+type SearchPage struct {
+	Hits       []SearchHit `json:"hits"`
+	Total      int         `json:"total"`
+	Limit      int         `json:"limit"`
+	NextCursor string      `json:"next_cursor,omitempty"`
+}
+
+// searchCursor is the position of the last hit of a page.
+type searchCursor struct {
+	Rank     float32 `json:"r"`
+	TargetID int     `json:"t"`
+	NoteID   int     `json:"n"`
+}
+
+func (q *SearchQuery) Validate(v *validator.Validator) {
+	v.Check(strings.TrimSpace(q.Text) != "", "q", validator.ErrEmptyFIeld.Error())
+	v.Check(q.Limit >= 0 && q.Limit <= paging.MaxLimit, "limit", fmt.Sprintf("must be between 1 and %d", paging.MaxLimit))
+	for _, status := range q.Statuses {
+		v.Check(validStatus(status), "status", fmt.Sprintf("unknown status %q", status))
+	}
+	if q.Cursor != "" {
+		var c searchCursor
+		v.Check(paging.DecodeCursor(q.Cursor, &c) == nil, "cursor", paging.ErrInvalidCursor.Error())
+	}
+}
+
+// hits builds the query listing every hit with its rank, the text to highlight
+// and the query that matched it, along with its arguments.
+func (q *SearchQuery) hits() (string, []any) {
+	args := []any{q.Text}
+	var conds []string
+	add := func(cond string, arg any) {
+		args = append(args, arg)
+		conds = append(conds, fmt.Sprintf(cond, len(args)))
+	}
+
+	if q.CatID != 0 {
+		add("m.cat_id = $%d", q.CatID)
+	}
+	if len(q.Statuses) > 0 {
+		statuses := make([]string, 0, len(q.Statuses))
+		for _, s := range q.Statuses {
+			statuses = append(statuses, string(s))
+		}
+		add("m.status = ANY($%d)", pq.Array(statuses))
+	}
+	if q.ExcludeCompleted {
+		conds = append(conds, "NOT t.is_completed")
+	}
+
+	where := ""
+	if len(conds) > 0 {
+		where = "AND " + strings.Join(conds, " AND ")
+	}
+
+	query := fmt.Sprintf(`
+		WITH q AS (
+			SELECT websearch_to_tsquery('simple', $1) AS simple,
+				websearch_to_tsquery('english', $1) AS english
+		),
+		hits AS (
+			SELECT '%[1]s' AS kind, m.id AS mission_id, m.status AS mission_status,
+				t.id AS target_id, 0 AS note_id, ts_rank(t.search, q.simple) AS rank,
+				'simple'::regconfig AS config, q.simple AS query, t.name || ', ' || t.country_name AS doc
+			FROM targets t
+			JOIN missions m ON m.id = t.mission_id
+			CROSS JOIN q
+			WHERE t.search @@ q.simple %[3]s
+			UNION ALL
+			SELECT '%[2]s', m.id, m.status,
+				t.id, n.id, ts_rank(n.search, q.english),
+				'english'::regconfig, q.english, n.body
+			FROM target_notes n
+			JOIN targets t ON t.id = n.target_id
+			JOIN missions m ON m.id = t.mission_id
+			CROSS JOIN q
+			WHERE n.search @@ q.english %[3]s
+		)`, HitTarget, HitNote, where)
+
+	return query, args
+}
+
+// Search ranks the targets and notes matching a full-text query.
+func (r *Repository) Search(ctx context.Context, query SearchQuery) (*SearchPage, error) {
+	hits, args := query.hits()
+
+	limit := paging.Limit(query.Limit)
+	page := &SearchPage{Hits: []SearchHit{}, Limit: limit}
+	if err := r.DB.QueryRowContext(ctx, hits+` SELECT COUNT(*) FROM hits`, args...).Scan(&page.Total); err != nil {
+		return nil, err
+	}
+
+	where := ""
+	if query.Cursor != "" {
+		var c searchCursor
+		if err := paging.DecodeCursor(query.Cursor, &c); err != nil {
+			return nil, err
+		}
+		args = append(args, c.Rank, c.TargetID, c.NoteID)
+		where = fmt.Sprintf(
+			"WHERE rank < $%[1]d::real OR (rank = $%[1]d::real AND (target_id, note_id) > ($%[2]d, $%[3]d))",
+			len(args)-2, len(args)-1, len(args),
+		)
+	}
+
+	// Snippets are only highlighted for the returned page
+	args = append(args, limit+1)
+	pageQuery := fmt.Sprintf(`%s
+		SELECT kind, mission_id, mission_status, target_id, note_id, rank,
+			ts_headline(config, %s, query, '%s')
+		FROM (
+			SELECT * FROM hits
+			%s
+			ORDER BY rank DESC, target_id, note_id
+			LIMIT $%d
+		) h
+		ORDER BY rank DESC, target_id, note_id
+	`, hits, escapedDoc, headlineOptions, where, len(args))
+
+	rows, err := r.DB.QueryContext(ctx, pageQuery, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var hit SearchHit
+		if err := rows.Scan(
+			&hit.Kind,
+			&hit.MissionID,
+			&hit.MissionStatus,
+			&hit.TargetID,
+			&hit.NoteID,
+			&hit.Rank,
+			&hit.Snippet,
+		); err != nil {
+			return nil, err
+		}
+		hit.MissionURL = fmt.Sprintf("/missions/get/%d", hit.MissionID)
+		page.Hits = append(page.Hits, hit)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(page.Hits) > limit {
+		page.Hits = page.Hits[:limit]
+		last := page.Hits[limit-1]
+		page.NextCursor = paging.EncodeCursor(searchCursor{
+			Rank:     last.Rank,
+			TargetID: last.TargetID,
+			NoteID:   last.NoteID,
+		})
+	}
+
+	return page, nil
+}
@@ -0,0 +1,37 @@
+package missions
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"spy-cat-agency/internal/models"
+	"spy-cat-agency/internal/storage/storagetest"
+)
+
+func TestSearchEscapesSnippets(t *testing.T) {
+	db := storagetest.Open(t)
+	repo := NewRepository(db)
+	mission := createTestMission(t, repo, 1)
+
+	body := `<img src=x onerror="alert(1)"> meet the informant at the docks`
+	if _, err := repo.AddNote(context.Background(), mission.Targets[0].ID, models.Note{Author: "handler", Body: body}); err != nil {
+		t.Fatal(err)
+	}
+
+	page, err := repo.Search(context.Background(), SearchQuery{Text: "informant"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Hits) != 1 {
+		t.Fatalf("got %d hits, want 1", len(page.Hits))
+	}
+
+	snippet := page.Hits[0].Snippet
+	if strings.Contains(snippet, "<img") {
+		t.Errorf("snippet %q contains unescaped markup", snippet)
+	}
+	if !strings.Contains(snippet, "<mark>informant</mark>") {
+		t.Errorf("snippet %q doesn't highlight the match", snippet)
+	}
+}
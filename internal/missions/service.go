@@ -1,7 +1,8 @@
 package missions
 
-func NewService(repo *Repository) *Service {
+func NewService(repo *Repository, policy Policy) *Service {
 	return &Service{
-		Repo: repo,
+		Repo:   repo,
+		Policy: policy,
 	}
 }
@@ -0,0 +1,62 @@
+package missions
+
+import (
+	"errors"
+	"fmt"
+
+	"spy-cat-agency/internal/models"
+)
+
+var (
+	ErrInvalidTransition = errors.New("Invalid mission status transition")
+	ErrOpenTargets       = errors.New("Mission has incomplete targets")
+)
+
+// transitions lists the statuses every mission status can move to.
+// Completed and aborted missions are final, an assigned mission goes
+// back to draft when its cat is unassigned.
+var transitions = map[models.MissionStatus][]models.MissionStatus{
+	models.MissionDraft:      {models.MissionAssigned, models.MissionAborted},
+	models.MissionAssigned:   {models.MissionInProgress, models.MissionAborted, models.MissionDraft},
+	models.MissionInProgress: {models.MissionCompleted, models.MissionAborted},
+}
+
+func validStatus(status models.MissionStatus) bool {
+	switch status {
+	case models.MissionDraft, models.MissionAssigned, models.MissionInProgress,
+		models.MissionCompleted, models.MissionAborted:
+		return true
+	default:
+		return false
+	}
+}
+
+func CanTransition(from, to models.MissionStatus) bool {
+	for _, next := range transitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+func transitionError(from, to models.MissionStatus) error {
+	return fmt.Errorf("%w: %s -> %s", ErrInvalidTransition, from, to)
+}
+
+// canAssign reports whether a cat can be (re)assigned to a mission in the given status.
+func canAssign(status models.MissionStatus) bool {
+	return status == models.MissionDraft || status == models.MissionAssigned
+}
+
+// lockedError returns the error for editing a mission in a final status, or nil.
+func lockedError(status models.MissionStatus) error {
+	switch status {
+	case models.MissionCompleted:
+		return ErrMIssionCompleted
+	case models.MissionAborted:
+		return ErrMissionAborted
+	default:
+		return nil
+	}
+}
@@ -1,8 +0,0 @@
-package missions
-
-type Target struct {
-	Name     string   `json:"name"`
-	Country  string   `json:"country"`
-	Notes    []string `json:"notes"`
-	Complete bool     `json:"complete"`
-}
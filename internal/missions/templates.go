@@ -0,0 +1,328 @@
+package missions
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"spy-cat-agency/internal/countries"
+	"spy-cat-agency/internal/models"
+	"spy-cat-agency/internal/storage"
+
+	"github.com/lib/pq"
+)
+
+var (
+	ErrTemplateExists   = errors.New("Template already exists")
+	ErrTemplateOutdated = errors.New("Template has a newer version")
+	ErrTemplateNotFound = errors.New("Template not found")
+)
+
+// FromTemplate creates a mission from a template version.
+type FromTemplate struct {
+	TemplateID int        `json:"template_id"`
+	CatID      int        `json:"cat_id,omitempty"`
+	DueAt      *time.Time `json:"due_at,omitempty"`
+	// Author signs the template's default notes.
+	Author string `json:"author"`
+}
+
+// CreateTemplate adds the first version of a template.
+func (r *Repository) CreateTemplate(ctx context.Context, template *models.MissionTemplate) (*models.MissionTemplate, error) {
+	var created *models.MissionTemplate
+	err := storage.WithTx(ctx, r.DB, nil, func(tx *sql.Tx) error {
+		if err := lockTemplateName(ctx, tx, template.Name); err != nil {
+			return err
+		}
+
+		var exists bool
+		existsQuery := `
+			SELECT EXISTS(SELECT 1 FROM mission_templates WHERE name = $1)
+		`
+		if err := tx.QueryRowContext(ctx, existsQuery, template.Name).Scan(&exists); err != nil {
+			return err
+		}
+		if exists {
+			return ErrTemplateExists
+		}
+
+		var err error
+		created, err = insertTemplate(ctx, tx, template.Name, 1, template.Targets)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+// UpdateTemplate adds a new version of a template with the given targets.
+// The version updated must be the latest one, otherwise ErrTemplateOutdated is
+// returned so changes made in the meantime aren't lost.
+func (r *Repository) UpdateTemplate(ctx context.Context, id int, targets []models.TemplateTarget) (*models.MissionTemplate, error) {
+	var updated *models.MissionTemplate
+	err := storage.WithTx(ctx, r.DB, nil, func(tx *sql.Tx) error {
+		var (
+			name    string
+			version int
+		)
+		templateQuery := `
+			SELECT name, version FROM mission_templates WHERE id = $1
+		`
+		if err := tx.QueryRowContext(ctx, templateQuery, id).Scan(&name, &version); err != nil {
+			return err
+		}
+
+		if err := lockTemplateName(ctx, tx, name); err != nil {
+			return err
+		}
+
+		var latest int
+		latestQuery := `
+			SELECT MAX(version) FROM mission_templates WHERE name = $1
+		`
+		if err := tx.QueryRowContext(ctx, latestQuery, name).Scan(&latest); err != nil {
+			return err
+		}
+		if latest != version {
+			return fmt.Errorf("%w: latest version is %d", ErrTemplateOutdated, latest)
+		}
+
+		var err error
+		updated, err = insertTemplate(ctx, tx, name, version+1, targets)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return updated, nil
+}
+
+// DeleteTemplate deletes all the versions of a template. Missions created
+// from it are kept.
+func (r *Repository) DeleteTemplate(ctx context.Context, id int) error {
+	query := `
+		DELETE FROM mission_templates
+		WHERE name = (SELECT name FROM mission_templates WHERE id = $1)
+	`
+	res, err := r.DB.ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (r *Repository) GetTemplate(ctx context.Context, id int) (*models.MissionTemplate, error) {
+	templates, err := queryTemplates(ctx, r.DB, `WHERE id = $1`, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(templates) == 0 {
+		return nil, sql.ErrNoRows
+	}
+
+	return &templates[0], nil
+}
+
+// ListTemplates returns the latest version of every template, by name.
+func (r *Repository) ListTemplates(ctx context.Context) ([]models.MissionTemplate, error) {
+	return queryTemplates(ctx, r.DB, `
+		WHERE (name, version) IN (
+			SELECT name, MAX(version) FROM mission_templates GROUP BY name
+		)
+	`)
+}
+
+// TemplateVersions returns all the versions of a template, oldest first.
+func (r *Repository) TemplateVersions(ctx context.Context, id int) ([]models.MissionTemplate, error) {
+	templates, err := queryTemplates(ctx, r.DB, `
+		WHERE name = (SELECT name FROM mission_templates WHERE id = $1)
+	`, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(templates) == 0 {
+		return nil, sql.ErrNoRows
+	}
+
+	return templates, nil
+}
+
+// CreateFromTemplate creates a mission with the targets and default notes of a
+// template version, the same way Create does.
+func (r *Repository) CreateFromTemplate(ctx context.Context, from FromTemplate, policy Policy) (*models.Mission, error) {
+	var mission *models.Mission
+	err := storage.WithTx(ctx, r.DB, nil, func(tx *sql.Tx) error {
+		// Keep the template from being deleted until the mission references it
+		lockQuery := `
+			SELECT id FROM mission_templates WHERE id = $1 FOR SHARE
+		`
+		var templateID int
+		err := tx.QueryRowContext(ctx, lockQuery, from.TemplateID).Scan(&templateID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrTemplateNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		templates, err := queryTemplates(ctx, tx, `WHERE id = $1`, templateID)
+		if err != nil {
+			return err
+		}
+		template := templates[0]
+
+		if err := policy.checkTargets(len(template.Targets)); err != nil {
+			return err
+		}
+
+		newMission := &models.Mission{
+			CatID:      from.CatID,
+			DueAt:      from.DueAt,
+			TemplateID: template.ID,
+			Targets:    make([]models.Target, 0, len(template.Targets)),
+		}
+		for _, t := range template.Targets {
+			target := models.Target{Name: t.Name, Country: t.Country}
+			for _, body := range t.Notes {
+				target.Notes = append(target.Notes, models.Note{Author: from.Author, Body: body})
+			}
+			newMission.Targets = append(newMission.Targets, target)
+		}
+
+		mission, err = createMission(ctx, tx, newMission)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return mission, nil
+}
+
+// lockTemplateName serializes the writes to the versions of a template.
+func lockTemplateName(ctx context.Context, tx *sql.Tx, name string) error {
+	_, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext('template:' || $1))`, name)
+	return err
+}
+
+func insertTemplate(ctx context.Context, tx *sql.Tx, name string, version int, targets []models.TemplateTarget) (*models.MissionTemplate, error) {
+	template := &models.MissionTemplate{
+		Name:    name,
+		Version: version,
+		Targets: make([]models.TemplateTarget, 0, len(targets)),
+	}
+
+	insertTemplateQuery := `
+		INSERT INTO mission_templates (name, version)
+		VALUES ($1, $2)
+		RETURNING id, created_at
+	`
+	if err := tx.QueryRowContext(ctx, insertTemplateQuery, name, version).Scan(&template.ID, &template.CreatedAt); err != nil {
+		return nil, err
+	}
+
+	insertTargetQuery := `
+		INSERT INTO template_targets (template_id, position, name, country, notes)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+	stmt, err := tx.PrepareContext(ctx, insertTargetQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	for i, t := range targets {
+		if t.Notes == nil {
+			t.Notes = []string{}
+		}
+		if _, err := stmt.ExecContext(ctx, template.ID, i, t.Name, t.Country, pq.Array(t.Notes)); err != nil {
+			return nil, err
+		}
+		t.CountryName = countries.Name(t.Country)
+		template.Targets = append(template.Targets, t)
+	}
+
+	return template, nil
+}
+
+// queryTemplates returns the templates matching the WHERE clause with their
+// targets, ordered by name and version.
+func queryTemplates(ctx context.Context, q querier, where string, args ...any) ([]models.MissionTemplate, error) {
+	query := `
+		SELECT id, name, version, created_at FROM mission_templates
+		` + where + `
+		ORDER BY name, version
+	`
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	templates := []models.MissionTemplate{}
+	for rows.Next() {
+		template := models.MissionTemplate{Targets: []models.TemplateTarget{}}
+		if err := rows.Scan(&template.ID, &template.Name, &template.Version, &template.CreatedAt); err != nil {
+			return nil, err
+		}
+		templates = append(templates, template)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	if len(templates) == 0 {
+		return templates, nil
+	}
+
+	ids := make([]int64, 0, len(templates))
+	byTemplate := make(map[int]int, len(templates))
+	for i, t := range templates {
+		ids = append(ids, int64(t.ID))
+		byTemplate[t.ID] = i
+	}
+
+	targetsQuery := `
+		SELECT template_id, name, country, notes FROM template_targets
+		WHERE template_id = ANY($1)
+		ORDER BY template_id, position
+	`
+	targetRows, err := q.QueryContext(ctx, targetsQuery, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer targetRows.Close()
+
+	for targetRows.Next() {
+		var (
+			templateID int
+			target     models.TemplateTarget
+		)
+		if err := targetRows.Scan(&templateID, &target.Name, &target.Country, pq.Array(&target.Notes)); err != nil {
+			return nil, err
+		}
+		target.CountryName = countries.Name(target.Country)
+		t := &templates[byTemplate[templateID]]
+		t.Targets = append(t.Targets, target)
+	}
+	if err := targetRows.Err(); err != nil {
+		return nil, err
+	}
+
+	return templates, nil
+}
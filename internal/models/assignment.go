@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// Assignment is a period during which a cat was assigned to a mission.
+type Assignment struct {
+	ID           int        `json:"id"`
+	MissionID    int        `json:"mission_id"`
+	CatID        int64      `json:"cat_id"`
+	AssignedAt   time.Time  `json:"assigned_at"`
+	UnassignedAt *time.Time `json:"unassigned_at,omitempty"`
+	// UnassignReason is unassigned, reassigned, cat_removed, or completed or
+	// aborted when the mission ended.
+	UnassignReason string `json:"unassign_reason,omitempty"`
+}
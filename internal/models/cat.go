@@ -1,10 +1,36 @@
 package models
 
+import "time"
+
+// DutyStatus is set by operators to take a cat off duty.
+type DutyStatus string
+
+const (
+	DutyActive  DutyStatus = "active"
+	DutyOnLeave DutyStatus = "on_leave"
+	DutyRetired DutyStatus = "retired"
+)
+
+// CatStatus is derived from the duty status and the missions of a cat.
+type CatStatus string
+
+const (
+	CatAvailable CatStatus = "available"
+	CatOnMission CatStatus = "on_mission"
+	CatOnLeave   CatStatus = "on_leave"
+	CatRetired   CatStatus = "retired"
+)
+
 type Cat struct {
-	ID                int64   `json:"id,omitempty"`
-	Name              string  `json:"name,omitempty"`
-	YearsOfExperience int8    `json:"yoe,omitempty"`
-	Breed             string  `json:"breed,omitempty"`
-	Salary            float64 `json:"salary,omitempty"`
-	MissionID         int     `json:"mission_id,omitempty"`
+	ID                int64      `json:"id,omitempty"`
+	Name              string     `json:"name,omitempty"`
+	YearsOfExperience int8       `json:"yoe,omitempty"`
+	Breed             string     `json:"breed,omitempty"`
+	Salary            Money      `json:"salary,omitempty" swaggertype:"number"`
+	Currency          string     `json:"currency,omitempty"`
+	DutyStatus        DutyStatus `json:"duty_status,omitempty" swaggerignore:"true"`
+	Status            CatStatus  `json:"status,omitempty" swaggerignore:"true"`
+	// MissionID is the active mission of the cat, if any.
+	MissionID int        `json:"mission_id,omitempty"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty" swaggerignore:"true"`
 }
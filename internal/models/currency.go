@@ -0,0 +1,18 @@
+package models
+
+const DefaultCurrency = "USD"
+
+// currencies are the ISO 4217 codes salaries can be paid in.
+var currencies = map[string]struct{}{
+	"AED": {}, "ARS": {}, "AUD": {}, "BGN": {}, "BRL": {}, "CAD": {}, "CHF": {}, "CLP": {},
+	"CNY": {}, "COP": {}, "CZK": {}, "DKK": {}, "EGP": {}, "EUR": {}, "GBP": {}, "GEL": {},
+	"HKD": {}, "HUF": {}, "IDR": {}, "ILS": {}, "INR": {}, "ISK": {}, "JPY": {}, "KRW": {},
+	"KZT": {}, "MXN": {}, "MYR": {}, "NOK": {}, "NZD": {}, "PHP": {}, "PLN": {}, "RON": {},
+	"SAR": {}, "SEK": {}, "SGD": {}, "THB": {}, "TRY": {}, "TWD": {}, "UAH": {}, "USD": {},
+	"VND": {}, "ZAR": {},
+}
+
+func ValidCurrency(code string) bool {
+	_, ok := currencies[code]
+	return ok
+}
@@ -4,10 +4,28 @@ import (
 	"time"
 )
 
+type MissionStatus string
+
+const (
+	MissionDraft      MissionStatus = "draft"
+	MissionAssigned   MissionStatus = "assigned"
+	MissionInProgress MissionStatus = "in_progress"
+	MissionCompleted  MissionStatus = "completed"
+	MissionAborted    MissionStatus = "aborted"
+)
+
 type Mission struct {
-	ID          int       `json:"id,omitempty"`
-	CatID       int       `json:"cat_id,omitempty"`
-	IsCompleted bool      `json:"is_completed,omitempty"`
-	CreatedAt   time.Time `json:"created_at,omitzero"`
-	Targets     []Target  `json:"targets,omitempty"`
+	ID        int           `json:"id,omitempty"`
+	CatID     int           `json:"cat_id,omitempty"`
+	Status    MissionStatus `json:"status,omitempty"`
+	CreatedAt time.Time     `json:"created_at,omitzero"`
+	DueAt     *time.Time    `json:"due_at,omitempty"`
+	// OverdueAt is when the mission was found past its due date.
+	OverdueAt *time.Time `json:"overdue_at,omitempty" swaggerignore:"true"`
+	// TemplateID is the template version the mission was created from.
+	TemplateID int `json:"template_id,omitempty" swaggerignore:"true"`
+	// SourceMissionID is the mission this one was cloned from.
+	SourceMissionID int      `json:"source_mission_id,omitempty" swaggerignore:"true"`
+	Targets         []Target `json:"targets,omitempty"`
+	Cat             *Cat     `json:"cat,omitempty"`
 }
@@ -0,0 +1,144 @@
+package models
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrMoneyFormat    = errors.New("must be a decimal amount")
+	ErrMoneyPrecision = errors.New("can't have more than two decimal places")
+	ErrMoneyRange     = errors.New("amount is too large")
+)
+
+// MoneyError is returned when a JSON amount can't be read as Money.
+type MoneyError struct {
+	Value string
+	Err   error
+}
+
+func (e *MoneyError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *MoneyError) Unwrap() error {
+	return e.Err
+}
+
+// Money is an exact amount in cents. It is read from and written to
+// JSON and SQL as a decimal with two fractional digits, e.g. 1234.50.
+type Money int64
+
+// ParseMoney parses a decimal amount such as "12", "-0.5" or "1234.56".
+func ParseMoney(s string) (Money, error) {
+	negative := strings.HasPrefix(s, "-")
+	digits := strings.TrimPrefix(s, "-")
+
+	whole, frac, hasFrac := strings.Cut(digits, ".")
+	if whole == "" || (hasFrac && frac == "") || !isDigits(whole) || !isDigits(frac) {
+		return 0, ErrMoneyFormat
+	}
+
+	frac = strings.TrimRight(frac, "0")
+	if len(frac) > 2 {
+		return 0, ErrMoneyPrecision
+	}
+
+	units, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil || units > (1<<63-1)/100-1 {
+		return 0, ErrMoneyRange
+	}
+
+	cents, _ := strconv.ParseInt((frac + "00")[:2], 10, 64)
+	m := Money(units*100 + cents)
+	if negative {
+		m = -m
+	}
+
+	return m, nil
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func (m Money) String() string {
+	sign := ""
+	cents := int64(m)
+	if cents < 0 {
+		sign = "-"
+		cents = -cents
+	}
+
+	return fmt.Sprintf("%s%d.%02d", sign, cents/100, cents%100)
+}
+
+func (m Money) IsNegative() bool {
+	return m < 0
+}
+
+func (m Money) Add(other Money) Money {
+	return m + other
+}
+
+// Mul multiplies the amount by a whole number.
+func (m Money) Mul(n int64) Money {
+	return m * Money(n)
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalJSON accepts the amount as a JSON number or string.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	raw := strings.Trim(string(data), `"`)
+	if raw == "null" {
+		return nil
+	}
+
+	parsed, err := ParseMoney(raw)
+	if err != nil {
+		return &MoneyError{Value: raw, Err: err}
+	}
+
+	*m = parsed
+	return nil
+}
+
+// Scan reads a NUMERIC column.
+func (m *Money) Scan(src any) error {
+	var raw string
+	switch v := src.(type) {
+	case []byte:
+		raw = string(v)
+	case string:
+		raw = v
+	case int64:
+		*m = Money(v * 100)
+		return nil
+	default:
+		return fmt.Errorf("cannot scan %T into Money", src)
+	}
+
+	parsed, err := ParseMoney(raw)
+	if err != nil {
+		return fmt.Errorf("cannot scan %q into Money: %w", raw, err)
+	}
+
+	*m = parsed
+	return nil
+}
+
+// Value writes the amount as a decimal string for NUMERIC columns.
+func (m Money) Value() (driver.Value, error) {
+	return m.String(), nil
+}
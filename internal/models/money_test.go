@@ -0,0 +1,162 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Money
+		err  error
+	}{
+		{in: "12", want: 12_00},
+		{in: "0.5", want: 50},
+		{in: "-0.5", want: -50},
+		{in: "1234.56", want: 1234_56},
+		{in: "1.50000", want: 1_50},
+		{in: "007.10", want: 7_10},
+		{in: "", err: ErrMoneyFormat},
+		{in: "-", err: ErrMoneyFormat},
+		{in: ".5", err: ErrMoneyFormat},
+		{in: "5.", err: ErrMoneyFormat},
+		{in: "1,5", err: ErrMoneyFormat},
+		{in: "1e3", err: ErrMoneyFormat},
+		{in: "+5", err: ErrMoneyFormat},
+		{in: "12 USD", err: ErrMoneyFormat},
+		{in: "1.234", err: ErrMoneyPrecision},
+		{in: "92233720368547758", err: ErrMoneyRange},
+		{in: "99999999999999999999", err: ErrMoneyRange},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseMoney(tt.in)
+		if !errors.Is(err, tt.err) {
+			t.Errorf("ParseMoney(%q) error = %v, want %v", tt.in, err, tt.err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseMoney(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestMoneyString(t *testing.T) {
+	tests := []struct {
+		in   Money
+		want string
+	}{
+		{in: 0, want: "0.00"},
+		{in: 5, want: "0.05"},
+		{in: -50, want: "-0.50"},
+		{in: 1234_56, want: "1234.56"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.in.String(); got != tt.want {
+			t.Errorf("Money(%d).String() = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestMoneyScan(t *testing.T) {
+	tests := []struct {
+		name string
+		src  any
+		want Money
+		fail bool
+	}{
+		{name: "numeric bytes", src: []byte("1234.50"), want: 1234_50},
+		{name: "numeric string", src: "0.99", want: 99},
+		{name: "integer", src: int64(42), want: 42_00},
+		{name: "malformed", src: []byte("NaN"), fail: true},
+		{name: "too precise", src: "1.001", fail: true},
+		{name: "unsupported type", src: 1.5, fail: true},
+		{name: "null", src: nil, fail: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var m Money
+			err := m.Scan(tt.src)
+			if (err != nil) != tt.fail {
+				t.Fatalf("Scan(%v) error = %v, want failure %v", tt.src, err, tt.fail)
+			}
+			if m != tt.want {
+				t.Errorf("Scan(%v) = %d, want %d", tt.src, m, tt.want)
+			}
+		})
+	}
+}
+
+func TestMoneyUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Money
+		err  error
+	}{
+		{in: `{"salary": 1500.25}`, want: 1500_25},
+		{in: `{"salary": "1500.25"}`, want: 1500_25},
+		{in: `{"salary": 100}`, want: 100_00},
+		{in: `{"salary": null}`, want: 0},
+		{in: `{}`, want: 0},
+		{in: `{"salary": "abc"}`, err: ErrMoneyFormat},
+		{in: `{"salary": 1e3}`, err: ErrMoneyFormat},
+		{in: `{"salary": 10.999}`, err: ErrMoneyPrecision},
+		{in: `{"salary": 99999999999999999999}`, err: ErrMoneyRange},
+	}
+
+	for _, tt := range tests {
+		var body struct {
+			Salary Money `json:"salary"`
+		}
+		err := json.Unmarshal([]byte(tt.in), &body)
+		if !errors.Is(err, tt.err) {
+			t.Errorf("Unmarshal(%s) error = %v, want %v", tt.in, err, tt.err)
+			continue
+		}
+		if err != nil {
+			// Handlers answer 422 for a *MoneyError and 400 for anything else
+			var moneyErr *MoneyError
+			if !errors.As(err, &moneyErr) {
+				t.Errorf("Unmarshal(%s) error %T isn't a *MoneyError", tt.in, err)
+			}
+			continue
+		}
+		if body.Salary != tt.want {
+			t.Errorf("Unmarshal(%s) = %d, want %d", tt.in, body.Salary, tt.want)
+		}
+	}
+}
+
+func TestMoneyJSONRoundTrip(t *testing.T) {
+	for _, m := range []Money{0, 1, -1_50, 1234_56} {
+		data, err := json.Marshal(m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got Money
+		if err := json.Unmarshal(data, &got); err != nil {
+			t.Fatalf("Unmarshal(%s): %v", data, err)
+		}
+		if got != m {
+			t.Errorf("round trip of %d gave %d", m, got)
+		}
+	}
+}
+
+func TestValidCurrency(t *testing.T) {
+	for _, code := range []string{"USD", "EUR", "JPY"} {
+		if !ValidCurrency(code) {
+			t.Errorf("ValidCurrency(%q) = false, want true", code)
+		}
+	}
+	// Handlers answer unknown codes with a 422, after upper-casing them
+	for _, code := range []string{"", "usd", "XXX", "US", "USDT"} {
+		if ValidCurrency(code) {
+			t.Errorf("ValidCurrency(%q) = true, want false", code)
+		}
+	}
+}
@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// Note is an entry of a target's notes journal.
+type Note struct {
+	ID        int       `json:"id,omitempty"`
+	TargetID  int       `json:"target_id,omitempty"`
+	Author    string    `json:"author"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"created_at,omitzero"`
+}
@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// OverdueEvent records a mission or target found past its due date.
+type OverdueEvent struct {
+	ID        int `json:"id"`
+	MissionID int `json:"mission_id"`
+	// TargetID is set when a target's deadline was missed.
+	TargetID   int       `json:"target_id,omitempty"`
+	DueAt      time.Time `json:"due_at"`
+	DetectedAt time.Time `json:"detected_at"`
+}
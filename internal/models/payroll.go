@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+type PayrollRun struct {
+	ID       int    `json:"id"`
+	Period   string `json:"period"`
+	Revision int    `json:"revision"`
+	// Bonuses are the bonus rates of the run by currency.
+	Bonuses   map[string]BonusRates `json:"bonuses"`
+	CreatedAt time.Time             `json:"created_at"`
+	// Totals are the sums of the line totals by currency.
+	Totals map[string]Money `json:"totals" swaggertype:"object,number"`
+	Lines  []PayrollLine    `json:"lines,omitempty"`
+}
+
+// BonusRates are the bonuses paid in one currency for every completed mission and target.
+type BonusRates struct {
+	PerMission Money `json:"mission" swaggertype:"number"`
+	PerTarget  Money `json:"target" swaggertype:"number"`
+}
+
+type PayrollLine struct {
+	CatID             int64  `json:"cat_id"`
+	CatName           string `json:"cat_name"`
+	Currency          string `json:"currency"`
+	Salary            Money  `json:"salary" swaggertype:"number"`
+	CompletedMissions int    `json:"completed_missions"`
+	CompletedTargets  int    `json:"completed_targets"`
+	Bonus             Money  `json:"bonus" swaggertype:"number"`
+	Total             Money  `json:"total" swaggertype:"number"`
+}
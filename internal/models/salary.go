@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+type SalaryChange struct {
+	ID        int       `json:"id"`
+	CatID     int64     `json:"cat_id"`
+	OldSalary Money     `json:"old_salary" swaggertype:"number"`
+	NewSalary Money     `json:"new_salary" swaggertype:"number"`
+	Currency  string    `json:"currency"`
+	Reason    string    `json:"reason,omitempty"`
+	Actor     string    `json:"actor"`
+	ChangedAt time.Time `json:"changed_at"`
+}
@@ -1,10 +1,17 @@
 package models
 
+import "time"
+
 type Target struct {
-	ID          int    `json:"id,omitempty"`
-	MissionID   int    `json:"mission_id,omitempty"`
-	Name        string `json:"name,omitempty"`
-	Country     string `json:"country,omitempty"`
-	Notes       string `json:"notes,omitempty"`
-	IsCompleted bool   `json:"is_completed,omitempty"`
+	ID        int    `json:"id,omitempty"`
+	MissionID int    `json:"mission_id,omitempty"`
+	Name      string `json:"name,omitempty"`
+	Country   string `json:"country,omitempty"`
+	// CountryName is the display name of the country's ISO 3166-1 alpha-2 code.
+	CountryName string     `json:"country_name,omitempty" swaggerignore:"true"`
+	Notes       []Note     `json:"notes,omitempty"`
+	IsCompleted bool       `json:"is_completed,omitempty"`
+	DueAt       *time.Time `json:"due_at,omitempty"`
+	// OverdueAt is when the target was found past its due date.
+	OverdueAt *time.Time `json:"overdue_at,omitempty" swaggerignore:"true"`
 }
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// MissionTemplate is a version of a named, reusable list of targets.
+type MissionTemplate struct {
+	ID        int              `json:"id,omitempty"`
+	Name      string           `json:"name"`
+	Version   int              `json:"version,omitempty"`
+	CreatedAt time.Time        `json:"created_at,omitzero"`
+	Targets   []TemplateTarget `json:"targets"`
+}
+
+type TemplateTarget struct {
+	Name    string `json:"name"`
+	Country string `json:"country"`
+	// CountryName is the display name of the country's ISO 3166-1 alpha-2 code.
+	CountryName string `json:"country_name,omitempty" swaggerignore:"true"`
+	// Notes are added to the target's journal when a mission is created.
+	Notes []string `json:"notes,omitempty"`
+}
@@ -0,0 +1,47 @@
+package payroll
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+
+	"spy-cat-agency/internal/models"
+)
+
+var csvHeader = []string{
+	"cat_id",
+	"cat_name",
+	"currency",
+	"salary",
+	"completed_missions",
+	"completed_targets",
+	"bonus",
+	"total",
+}
+
+// WriteCSV writes the lines of a payroll run as CSV.
+func WriteCSV(w io.Writer, run *models.PayrollRun) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+
+	for _, line := range run.Lines {
+		record := []string{
+			strconv.FormatInt(line.CatID, 10),
+			line.CatName,
+			line.Currency,
+			line.Salary.String(),
+			strconv.Itoa(line.CompletedMissions),
+			strconv.Itoa(line.CompletedTargets),
+			line.Bonus.String(),
+			line.Total.String(),
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
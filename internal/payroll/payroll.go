@@ -0,0 +1,188 @@
+package payroll
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"spy-cat-agency/internal/models"
+)
+
+var ErrInvalidBonus = errors.New("invalid payroll bonus")
+
+// Bonuses are the rates paid on top of the salary for work completed within
+// the period, by currency. Cats are paid the rates of their salary's
+// currency, and no bonus if it has none.
+type Bonuses map[string]models.BonusRates
+
+// ParseBonuses reads the per mission and per target bonus rates, each a comma
+// separated list of currency:amount pairs such as "USD:100,EUR:90". An amount
+// without a currency is in the default currency.
+func ParseBonuses(perMission, perTarget string) (Bonuses, error) {
+	bonuses := Bonuses{}
+	err := parseRates(perMission, func(currency string, amount models.Money) {
+		rates := bonuses[currency]
+		rates.PerMission = amount
+		bonuses[currency] = rates
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = parseRates(perTarget, func(currency string, amount models.Money) {
+		rates := bonuses[currency]
+		rates.PerTarget = amount
+		bonuses[currency] = rates
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Currencies without bonuses aren't stored with the runs
+	for currency, rates := range bonuses {
+		if rates == (models.BonusRates{}) {
+			delete(bonuses, currency)
+		}
+	}
+
+	return bonuses, nil
+}
+
+func parseRates(s string, set func(currency string, amount models.Money)) error {
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		currency, raw, found := strings.Cut(pair, ":")
+		if !found {
+			currency, raw = models.DefaultCurrency, pair
+		}
+		currency = strings.ToUpper(strings.TrimSpace(currency))
+		if !models.ValidCurrency(currency) {
+			return fmt.Errorf("%w: unknown currency %q", ErrInvalidBonus, currency)
+		}
+
+		amount, err := models.ParseMoney(strings.TrimSpace(raw))
+		if err != nil {
+			return fmt.Errorf("%w: %s amount %s", ErrInvalidBonus, currency, err)
+		}
+		if amount.IsNegative() {
+			return fmt.Errorf("%w: %s amount can't be negative", ErrInvalidBonus, currency)
+		}
+
+		set(currency, amount)
+	}
+
+	return nil
+}
+
+// RunResult is the outcome of running payroll for a period.
+type RunResult struct {
+	Run *models.PayrollRun `json:"run"`
+	// Created is false if the period was already run with the same result.
+	Created bool `json:"created"`
+	// Diff lists the lines that changed since the previous run of the period.
+	Diff []LineDiff `json:"diff"`
+	// Previous is the latest run of the period before this one, if any.
+	Previous *models.PayrollRun `json:"-"`
+}
+
+const (
+	LineAdded   = "added"
+	LineRemoved = "removed"
+	LineChanged = "changed"
+)
+
+type LineDiff struct {
+	CatID  int64               `json:"cat_id"`
+	Change string              `json:"change"`
+	Before *models.PayrollLine `json:"before,omitempty"`
+	After  *models.PayrollLine `json:"after,omitempty"`
+}
+
+type Service struct {
+	Repo
+	Bonuses Bonuses
+}
+
+// Run computes payroll for the period. Runs are idempotent: if nothing
+// changed since the latest run of the period that run is returned,
+// otherwise a new revision is stored along with its diff.
+func (s *Service) Run(ctx context.Context, period Period) (*RunResult, error) {
+	result, err := s.Repo.Run(ctx, period, s.Bonuses)
+	if err != nil {
+		return nil, err
+	}
+
+	result.Diff = []LineDiff{}
+	if result.Created && result.Previous != nil {
+		result.Diff = diff(result.Previous.Lines, result.Run.Lines)
+	}
+
+	return result, nil
+}
+
+func (s *Service) List(ctx context.Context, period string) ([]models.PayrollRun, error) {
+	return s.Repo.List(ctx, period)
+}
+
+func (s *Service) Get(ctx context.Context, id int) (*models.PayrollRun, error) {
+	return s.Repo.Get(ctx, id)
+}
+
+// computeLine fills in the bonus and total of a payroll line.
+// Bonuses are paid at the rates of the currency of the cat's salary.
+func computeLine(line *models.PayrollLine, bonuses Bonuses) {
+	rates := bonuses[line.Currency]
+	line.Bonus = rates.PerMission.Mul(int64(line.CompletedMissions)).
+		Add(rates.PerTarget.Mul(int64(line.CompletedTargets)))
+	line.Total = line.Salary.Add(line.Bonus)
+}
+
+// totals sums the line totals by currency.
+func totals(lines []models.PayrollLine) map[string]models.Money {
+	sums := map[string]models.Money{}
+	for _, line := range lines {
+		sums[line.Currency] = sums[line.Currency].Add(line.Total)
+	}
+	return sums
+}
+
+// sameLines reports whether two runs produced the same lines, both ordered by cat ID.
+func sameLines(a, b []models.PayrollLine) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// diff compares the lines of two runs, both ordered by cat ID.
+func diff(before, after []models.PayrollLine) []LineDiff {
+	diffs := []LineDiff{}
+	i, j := 0, 0
+	for i < len(before) || j < len(after) {
+		switch {
+		case j == len(after) || (i < len(before) && before[i].CatID < after[j].CatID):
+			diffs = append(diffs, LineDiff{CatID: before[i].CatID, Change: LineRemoved, Before: &before[i]})
+			i++
+		case i == len(before) || after[j].CatID < before[i].CatID:
+			diffs = append(diffs, LineDiff{CatID: after[j].CatID, Change: LineAdded, After: &after[j]})
+			j++
+		default:
+			if before[i] != after[j] {
+				diffs = append(diffs, LineDiff{CatID: after[j].CatID, Change: LineChanged, Before: &before[i], After: &after[j]})
+			}
+			i++
+			j++
+		}
+	}
+
+	return diffs
+}
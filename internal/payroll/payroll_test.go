@@ -0,0 +1,80 @@
+package payroll
+
+import (
+	"errors"
+	"maps"
+	"testing"
+
+	"spy-cat-agency/internal/models"
+)
+
+func TestParseBonuses(t *testing.T) {
+	tests := []struct {
+		name       string
+		perMission string
+		perTarget  string
+		want       Bonuses
+		err        error
+	}{
+		{name: "empty", want: Bonuses{}},
+		{
+			name:       "per currency",
+			perMission: "USD:100, eur:90",
+			perTarget:  "USD:25.50",
+			want: Bonuses{
+				"USD": {PerMission: 100_00, PerTarget: 25_50},
+				"EUR": {PerMission: 90_00},
+			},
+		},
+		{
+			name:       "default currency",
+			perMission: "100",
+			want:       Bonuses{models.DefaultCurrency: {PerMission: 100_00}},
+		},
+		{name: "zero rates", perMission: "USD:0", perTarget: "USD:0", want: Bonuses{}},
+		{name: "unknown currency", perMission: "XXX:100", err: ErrInvalidBonus},
+		{name: "malformed amount", perTarget: "USD:1O0", err: ErrInvalidBonus},
+		{name: "negative amount", perMission: "USD:-5", err: ErrInvalidBonus},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseBonuses(tt.perMission, tt.perTarget)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("ParseBonuses() error = %v, want %v", err, tt.err)
+			}
+			if !maps.Equal(got, tt.want) {
+				t.Errorf("ParseBonuses() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestComputeLineUsesCurrencyRates(t *testing.T) {
+	bonuses := Bonuses{
+		"USD": {PerMission: 100_00, PerTarget: 10_00},
+		"JPY": {PerMission: 15000_00, PerTarget: 1500_00},
+	}
+
+	tests := []struct {
+		currency  string
+		wantBonus models.Money
+	}{
+		{currency: "USD", wantBonus: 100_00 + 3*10_00},
+		{currency: "JPY", wantBonus: 15000_00 + 3*1500_00},
+		{currency: "EUR", wantBonus: 0},
+	}
+
+	for _, tt := range tests {
+		line := models.PayrollLine{
+			Currency:          tt.currency,
+			Salary:            1000_00,
+			CompletedMissions: 1,
+			CompletedTargets:  3,
+		}
+		computeLine(&line, bonuses)
+		if line.Bonus != tt.wantBonus || line.Total != line.Salary+tt.wantBonus {
+			t.Errorf("%s line: bonus %s, total %s, want bonus %s", tt.currency, line.Bonus, line.Total, tt.wantBonus)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package payroll
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrInvalidPeriod = errors.New("period must be a month formatted as YYYY-MM")
+	ErrFuturePeriod  = errors.New("period can't be in the future")
+)
+
+const periodLayout = "2006-01"
+
+// Period is a calendar month payroll is run for.
+type Period struct {
+	Start time.Time
+}
+
+func ParsePeriod(s string) (Period, error) {
+	start, err := time.Parse(periodLayout, s)
+	if err != nil {
+		return Period{}, ErrInvalidPeriod
+	}
+
+	return Period{Start: start}, nil
+}
+
+// End is the start of the following month.
+func (p Period) End() time.Time {
+	return p.Start.AddDate(0, 1, 0)
+}
+
+func (p Period) String() string {
+	return p.Start.Format(periodLayout)
+}
+
+// Future reports whether the period starts after the month of now.
+func (p Period) Future(now time.Time) bool {
+	now = now.UTC()
+	return p.Start.After(time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC))
+}
@@ -0,0 +1,374 @@
+package payroll
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"maps"
+
+	"spy-cat-agency/internal/models"
+
+	"github.com/lib/pq"
+)
+
+type Repo interface {
+	Get(ctx context.Context, id int) (*models.PayrollRun, error)
+	List(ctx context.Context, period string) ([]models.PayrollRun, error)
+	Run(ctx context.Context, period Period, bonuses Bonuses) (*RunResult, error)
+}
+
+type Repository struct {
+	*sql.DB
+}
+
+func NewRepository(db *sql.DB) *Repository {
+	return &Repository{
+		DB: db,
+	}
+}
+
+func (r *Repository) Run(ctx context.Context, period Period, bonuses Bonuses) (*RunResult, error) {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Serialize runs of the same period
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext('payroll:' || $1))`, period.String()); err != nil {
+		return nil, err
+	}
+
+	lines, err := computeLines(ctx, tx, period, bonuses)
+	if err != nil {
+		slog.Error("Payroll run", "compute lines", err)
+		return nil, err
+	}
+
+	previous, err := latestRun(ctx, tx, period.String())
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+
+	if previous != nil &&
+		maps.Equal(previous.Bonuses, bonuses) &&
+		sameLines(previous.Lines, lines) {
+		return &RunResult{Run: previous, Created: false}, nil
+	}
+
+	run := &models.PayrollRun{
+		Period:   period.String(),
+		Revision: 1,
+		Bonuses:  bonuses,
+		Lines:    lines,
+	}
+	if previous != nil {
+		run.Revision = previous.Revision + 1
+	}
+	run.Totals = totals(lines)
+
+	insertRunQuery := `
+		INSERT INTO payroll_runs (period, revision)
+		VALUES ($1, $2)
+		RETURNING id, created_at
+	`
+	err = tx.QueryRowContext(ctx, insertRunQuery,
+		run.Period,
+		run.Revision,
+	).Scan(&run.ID, &run.CreatedAt)
+	if err != nil {
+		slog.Error("Payroll run", "insert run", err)
+		return nil, err
+	}
+
+	insertBonusQuery := `
+		INSERT INTO payroll_run_bonuses (run_id, currency, mission_bonus, target_bonus)
+		VALUES ($1, $2, $3, $4)
+	`
+	for currency, rates := range run.Bonuses {
+		if _, err := tx.ExecContext(ctx, insertBonusQuery, run.ID, currency, rates.PerMission, rates.PerTarget); err != nil {
+			slog.Error("Payroll run", "insert bonus", err)
+			return nil, err
+		}
+	}
+
+	insertLineQuery := `
+		INSERT INTO payroll_lines (run_id, cat_id, cat_name, currency, salary, completed_missions, completed_targets, bonus, total)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+	stmt, err := tx.PrepareContext(ctx, insertLineQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	for _, line := range lines {
+		if _, err := stmt.ExecContext(ctx,
+			run.ID,
+			line.CatID,
+			line.CatName,
+			line.Currency,
+			line.Salary,
+			line.CompletedMissions,
+			line.CompletedTargets,
+			line.Bonus,
+			line.Total,
+		); err != nil {
+			slog.Error("Payroll run", "insert line", err)
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return &RunResult{Run: run, Created: true, Previous: previous}, nil
+}
+
+// computeLines builds a payroll line for every cat employed during the period,
+// counting the missions and targets completed within it. Cats created after
+// the period or removed before it are left out. Salaries are taken from the
+// salary ledger as they were at the end of the period, so running a past
+// period again doesn't pick up later raises.
+func computeLines(ctx context.Context, tx *sql.Tx, period Period, bonuses Bonuses) ([]models.PayrollLine, error) {
+	query := `
+		SELECT c.id, c.name, c.currency, COALESCE(
+			(
+				SELECT new_salary FROM salary_changes
+				WHERE cat_id = c.id AND changed_at < $2
+				ORDER BY changed_at DESC, id DESC
+				LIMIT 1
+			),
+			(
+				SELECT old_salary FROM salary_changes
+				WHERE cat_id = c.id AND changed_at >= $2
+				ORDER BY changed_at, id
+				LIMIT 1
+			),
+			c.salary
+		), COALESCE(m.completed, 0), COALESCE(t.completed, 0)
+		FROM cats c
+		LEFT JOIN (
+			SELECT cat_id, COUNT(*) AS completed FROM missions
+			WHERE status = 'completed' AND completed_at >= $1 AND completed_at < $2
+			GROUP BY cat_id
+		) m ON m.cat_id = c.id
+		LEFT JOIN (
+			SELECT ms.cat_id, COUNT(*) AS completed FROM targets tg
+			JOIN missions ms ON ms.id = tg.mission_id
+			WHERE tg.is_completed AND tg.completed_at >= $1 AND tg.completed_at < $2
+			GROUP BY ms.cat_id
+		) t ON t.cat_id = c.id
+		WHERE (c.deleted_at IS NULL OR c.deleted_at >= $1)
+			AND (c.created_at IS NULL OR c.created_at < $2)
+		ORDER BY c.id
+	`
+	rows, err := tx.QueryContext(ctx, query, period.Start, period.End())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	lines := []models.PayrollLine{}
+	for rows.Next() {
+		var line models.PayrollLine
+		if err := rows.Scan(
+			&line.CatID,
+			&line.CatName,
+			&line.Currency,
+			&line.Salary,
+			&line.CompletedMissions,
+			&line.CompletedTargets,
+		); err != nil {
+			return nil, err
+		}
+		computeLine(&line, bonuses)
+		lines = append(lines, line)
+	}
+
+	return lines, rows.Err()
+}
+
+func latestRun(ctx context.Context, tx *sql.Tx, period string) (*models.PayrollRun, error) {
+	query := `
+		SELECT id FROM payroll_runs
+		WHERE period = $1
+		ORDER BY revision DESC
+		LIMIT 1
+	`
+	var id int
+	if err := tx.QueryRowContext(ctx, query, period).Scan(&id); err != nil {
+		return nil, err
+	}
+
+	return getRun(ctx, tx, id)
+}
+
+// querier is implemented by both *sql.DB and *sql.Tx.
+type querier interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+func getRun(ctx context.Context, q querier, id int) (*models.PayrollRun, error) {
+	query := `
+		SELECT id, period, revision, created_at
+		FROM payroll_runs
+		WHERE id = $1
+	`
+	var run models.PayrollRun
+	err := q.QueryRowContext(ctx, query, id).Scan(
+		&run.ID,
+		&run.Period,
+		&run.Revision,
+		&run.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	linesQuery := `
+		SELECT cat_id, cat_name, currency, salary, completed_missions, completed_targets, bonus, total
+		FROM payroll_lines
+		WHERE run_id = $1
+		ORDER BY cat_id
+	`
+	rows, err := q.QueryContext(ctx, linesQuery, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	run.Lines = []models.PayrollLine{}
+	for rows.Next() {
+		var line models.PayrollLine
+		if err := rows.Scan(
+			&line.CatID,
+			&line.CatName,
+			&line.Currency,
+			&line.Salary,
+			&line.CompletedMissions,
+			&line.CompletedTargets,
+			&line.Bonus,
+			&line.Total,
+		); err != nil {
+			return nil, err
+		}
+		run.Lines = append(run.Lines, line)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	run.Totals = totals(run.Lines)
+
+	runs := []models.PayrollRun{run}
+	if err := loadBonuses(ctx, q, runs); err != nil {
+		return nil, err
+	}
+
+	return &runs[0], nil
+}
+
+// loadBonuses fills in the bonus rates of runs.
+func loadBonuses(ctx context.Context, q querier, runs []models.PayrollRun) error {
+	ids := make([]int64, 0, len(runs))
+	index := make(map[int]int, len(runs))
+	for i := range runs {
+		runs[i].Bonuses = map[string]models.BonusRates{}
+		ids = append(ids, int64(runs[i].ID))
+		index[runs[i].ID] = i
+	}
+
+	query := `
+		SELECT run_id, currency, mission_bonus, target_bonus
+		FROM payroll_run_bonuses
+		WHERE run_id = ANY($1)
+	`
+	rows, err := q.QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			runID    int
+			currency string
+			rates    models.BonusRates
+		)
+		if err := rows.Scan(&runID, &currency, &rates.PerMission, &rates.PerTarget); err != nil {
+			return err
+		}
+		runs[index[runID]].Bonuses[currency] = rates
+	}
+
+	return rows.Err()
+}
+
+func (r *Repository) Get(ctx context.Context, id int) (*models.PayrollRun, error) {
+	return getRun(ctx, r.DB, id)
+}
+
+// List returns the runs without their lines, newest first.
+// An empty period lists the runs of all periods.
+func (r *Repository) List(ctx context.Context, period string) ([]models.PayrollRun, error) {
+	query := `
+		SELECT r.id, r.period, r.revision, r.created_at,
+			COALESCE(l.currency, ''), COALESCE(l.total, 0)
+		FROM payroll_runs r
+		LEFT JOIN (
+			SELECT run_id, currency, SUM(total) AS total FROM payroll_lines
+			GROUP BY run_id, currency
+		) l ON l.run_id = r.id
+	`
+	var args []any
+	if period != "" {
+		query += `WHERE r.period = $1`
+		args = append(args, period)
+	}
+	query += ` ORDER BY r.period DESC, r.revision DESC, l.currency`
+
+	rows, err := r.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// A run has one row per currency of its lines
+	runs := []models.PayrollRun{}
+	for rows.Next() {
+		var (
+			run      models.PayrollRun
+			currency string
+			total    models.Money
+		)
+		if err := rows.Scan(
+			&run.ID,
+			&run.Period,
+			&run.Revision,
+			&run.CreatedAt,
+			&currency,
+			&total,
+		); err != nil {
+			return nil, err
+		}
+		if len(runs) == 0 || runs[len(runs)-1].ID != run.ID {
+			run.Totals = map[string]models.Money{}
+			runs = append(runs, run)
+		}
+		if currency != "" {
+			runs[len(runs)-1].Totals[currency] = total
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := loadBonuses(ctx, r.DB, runs); err != nil {
+		return nil, err
+	}
+
+	return runs, nil
+}
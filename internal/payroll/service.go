@@ -0,0 +1,8 @@
+package payroll
+
+func NewService(repo *Repository, bonuses Bonuses) *Service {
+	return &Service{
+		Repo:    repo,
+		Bonuses: bonuses,
+	}
+}
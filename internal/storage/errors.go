@@ -0,0 +1,15 @@
+package storage
+
+import (
+	"errors"
+
+	"github.com/lib/pq"
+)
+
+const uniqueViolation = "23505"
+
+// IsUniqueViolation reports whether err was caused by the given unique constraint or index.
+func IsUniqueViolation(err error, constraint string) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == uniqueViolation && pqErr.Constraint == constraint
+}
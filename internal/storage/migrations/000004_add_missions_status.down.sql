@@ -0,0 +1,5 @@
+ALTER TABLE missions ADD COLUMN is_completed BOOLEAN NOT NULL DEFAULT FALSE;
+
+UPDATE missions SET is_completed = (status = 'completed');
+
+ALTER TABLE missions DROP COLUMN status;
@@ -0,0 +1,13 @@
+ALTER TABLE missions ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'draft'
+    CONSTRAINT missions_status_check CHECK (status IN ('draft', 'assigned', 'in_progress', 'completed', 'aborted'));
+
+UPDATE missions m SET status = CASE
+    WHEN m.is_completed THEN 'completed'
+    WHEN m.cat_id IS NOT NULL AND EXISTS (
+        SELECT 1 FROM targets t WHERE t.mission_id = m.id AND t.is_completed
+    ) THEN 'in_progress'
+    WHEN m.cat_id IS NOT NULL THEN 'assigned'
+    ELSE 'draft'
+END;
+
+ALTER TABLE missions DROP COLUMN is_completed;
@@ -0,0 +1,17 @@
+CREATE TABLE breeds (
+    id VARCHAR(50) PRIMARY KEY,
+    name VARCHAR(50) NOT NULL UNIQUE
+);
+
+INSERT INTO breeds (id, name) VALUES
+    ('abys', 'Abyssinian'),
+    ('beng', 'Bengal'),
+    ('bsho', 'British Shorthair'),
+    ('mcoo', 'Maine Coon'),
+    ('pers', 'Persian'),
+    ('ragd', 'Ragdoll'),
+    ('rblu', 'Russian Blue'),
+    ('sfol', 'Scottish Fold'),
+    ('siam', 'Siamese'),
+    ('sphy', 'Sphynx')
+ON CONFLICT DO NOTHING;
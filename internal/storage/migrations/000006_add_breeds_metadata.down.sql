@@ -0,0 +1,6 @@
+ALTER TABLE breeds
+    DROP COLUMN temperament,
+    DROP COLUMN origin,
+    DROP COLUMN life_span,
+    DROP COLUMN weight_imperial,
+    DROP COLUMN weight_metric;
@@ -0,0 +1,26 @@
+ALTER TABLE breeds
+    ADD COLUMN temperament TEXT NOT NULL DEFAULT '',
+    ADD COLUMN origin VARCHAR(100) NOT NULL DEFAULT '',
+    ADD COLUMN life_span VARCHAR(20) NOT NULL DEFAULT '',
+    ADD COLUMN weight_imperial VARCHAR(20) NOT NULL DEFAULT '',
+    ADD COLUMN weight_metric VARCHAR(20) NOT NULL DEFAULT '';
+
+UPDATE breeds b SET
+    temperament = m.temperament,
+    origin = m.origin,
+    life_span = m.life_span,
+    weight_imperial = m.weight_imperial,
+    weight_metric = m.weight_metric
+FROM (VALUES
+    ('abys', 'Active, Energetic, Independent, Intelligent, Gentle', 'Egypt', '14 - 15', '7 - 10', '3 - 5'),
+    ('beng', 'Alert, Agile, Energetic, Demanding, Intelligent', 'United States', '12 - 15', '6 - 12', '3 - 7'),
+    ('bsho', 'Affectionate, Easy Going, Gentle, Loyal, Patient, Calm', 'United Kingdom', '12 - 17', '12 - 20', '5 - 9'),
+    ('mcoo', 'Adaptable, Intelligent, Loving, Gentle, Independent', 'United States', '12 - 15', '12 - 18', '5 - 8'),
+    ('pers', 'Affectionate, Loyal, Sedate, Quiet', 'Iran (Persia)', '14 - 15', '9 - 14', '4 - 6'),
+    ('ragd', 'Affectionate, Friendly, Gentle, Quiet, Easygoing', 'United States', '12 - 17', '12 - 20', '5 - 9'),
+    ('rblu', 'Active, Dependable, Easy Going, Gentle, Intelligent, Loyal, Playful, Quiet', 'Russia', '10 - 16', '5 - 11', '2 - 5'),
+    ('sfol', 'Affectionate, Intelligent, Loyal, Playful, Social, Sweet, Loving', 'United Kingdom', '11 - 14', '5 - 11', '2 - 5'),
+    ('siam', 'Active, Agile, Clever, Sociable, Loving, Energetic', 'Thailand', '12 - 15', '8 - 15', '4 - 7'),
+    ('sphy', 'Loyal, Inquisitive, Friendly, Quiet, Gentle', 'Canada', '12 - 14', '6 - 12', '3 - 5')
+) AS m (id, temperament, origin, life_span, weight_imperial, weight_metric)
+WHERE b.id = m.id;
@@ -0,0 +1,12 @@
+CREATE TABLE salary_changes (
+    id SERIAL PRIMARY KEY,
+    cat_id INT NOT NULL REFERENCES cats(id) ON DELETE CASCADE,
+    old_salary NUMERIC(10,2) NOT NULL,
+    new_salary NUMERIC(10,2) NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    actor VARCHAR(100) NOT NULL,
+    changed_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX salary_changes_cat_id_idx ON salary_changes (cat_id, changed_at);
+CREATE INDEX salary_changes_changed_at_idx ON salary_changes (changed_at);
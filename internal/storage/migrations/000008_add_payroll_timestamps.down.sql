@@ -0,0 +1,3 @@
+ALTER TABLE cats DROP COLUMN created_at;
+ALTER TABLE targets DROP COLUMN completed_at;
+ALTER TABLE missions DROP COLUMN completed_at;
@@ -0,0 +1,7 @@
+ALTER TABLE missions ADD COLUMN completed_at TIMESTAMP;
+ALTER TABLE targets ADD COLUMN completed_at TIMESTAMP;
+
+-- Cats created before this migration have no known hire date and are
+-- paid for every period, new cats only from the month they were created
+ALTER TABLE cats ADD COLUMN created_at TIMESTAMP;
+ALTER TABLE cats ALTER COLUMN created_at SET DEFAULT NOW();
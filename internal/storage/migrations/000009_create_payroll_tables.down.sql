@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS payroll_lines;
+DROP TABLE IF EXISTS payroll_runs;
+DROP FUNCTION IF EXISTS payroll_immutable();
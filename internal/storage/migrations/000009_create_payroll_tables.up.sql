@@ -0,0 +1,37 @@
+CREATE TABLE payroll_runs (
+    id SERIAL PRIMARY KEY,
+    period CHAR(7) NOT NULL,
+    revision INT NOT NULL,
+    mission_bonus NUMERIC(10,2) NOT NULL,
+    target_bonus NUMERIC(10,2) NOT NULL,
+    total NUMERIC(14,2) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT payroll_run_unique_revision UNIQUE (period, revision)
+);
+
+CREATE TABLE payroll_lines (
+    run_id INT NOT NULL REFERENCES payroll_runs(id),
+    cat_id INT NOT NULL,
+    cat_name VARCHAR(100) NOT NULL,
+    salary NUMERIC(10,2) NOT NULL,
+    completed_missions INT NOT NULL,
+    completed_targets INT NOT NULL,
+    bonus NUMERIC(12,2) NOT NULL,
+    total NUMERIC(12,2) NOT NULL,
+    PRIMARY KEY (run_id, cat_id)
+);
+
+-- Payroll runs are immutable once written
+CREATE FUNCTION payroll_immutable() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'payroll runs are immutable';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER payroll_runs_immutable
+    BEFORE UPDATE OR DELETE ON payroll_runs
+    FOR EACH ROW EXECUTE FUNCTION payroll_immutable();
+
+CREATE TRIGGER payroll_lines_immutable
+    BEFORE UPDATE OR DELETE ON payroll_lines
+    FOR EACH ROW EXECUTE FUNCTION payroll_immutable();
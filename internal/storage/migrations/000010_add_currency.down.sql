@@ -0,0 +1,23 @@
+ALTER TABLE payroll_runs ADD COLUMN mission_bonus NUMERIC(10,2) NOT NULL DEFAULT 0;
+ALTER TABLE payroll_runs ADD COLUMN target_bonus NUMERIC(10,2) NOT NULL DEFAULT 0;
+ALTER TABLE payroll_runs ADD COLUMN total NUMERIC(14,2) NOT NULL DEFAULT 0;
+
+-- Runs keep a single rate, the USD one if they have it
+ALTER TABLE payroll_runs DISABLE TRIGGER payroll_runs_immutable;
+UPDATE payroll_runs r SET mission_bonus = b.mission_bonus, target_bonus = b.target_bonus
+FROM (
+    SELECT DISTINCT ON (run_id) run_id, mission_bonus, target_bonus
+    FROM payroll_run_bonuses
+    ORDER BY run_id, currency <> 'USD', currency
+) b
+WHERE b.run_id = r.id;
+UPDATE payroll_runs r SET total = COALESCE((
+    SELECT SUM(l.total) FROM payroll_lines l WHERE l.run_id = r.id
+), 0);
+ALTER TABLE payroll_runs ENABLE TRIGGER payroll_runs_immutable;
+
+DROP TABLE IF EXISTS payroll_run_bonuses;
+
+ALTER TABLE payroll_lines DROP COLUMN currency;
+ALTER TABLE salary_changes DROP COLUMN currency;
+ALTER TABLE cats DROP COLUMN currency;
@@ -0,0 +1,27 @@
+ALTER TABLE cats ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'USD';
+ALTER TABLE salary_changes ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'USD';
+ALTER TABLE payroll_lines ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'USD';
+
+-- Run totals are computed per currency from the lines
+ALTER TABLE payroll_runs DROP COLUMN total;
+
+-- Bonus rates are set per currency
+CREATE TABLE payroll_run_bonuses (
+    run_id INT NOT NULL REFERENCES payroll_runs(id),
+    currency CHAR(3) NOT NULL,
+    mission_bonus NUMERIC(10,2) NOT NULL,
+    target_bonus NUMERIC(10,2) NOT NULL,
+    PRIMARY KEY (run_id, currency)
+);
+
+-- Earlier runs only paid in USD
+INSERT INTO payroll_run_bonuses (run_id, currency, mission_bonus, target_bonus)
+SELECT id, 'USD', mission_bonus, target_bonus FROM payroll_runs
+WHERE mission_bonus <> 0 OR target_bonus <> 0;
+
+ALTER TABLE payroll_runs DROP COLUMN mission_bonus;
+ALTER TABLE payroll_runs DROP COLUMN target_bonus;
+
+CREATE TRIGGER payroll_run_bonuses_immutable
+    BEFORE UPDATE OR DELETE ON payroll_run_bonuses
+    FOR EACH ROW EXECUTE FUNCTION payroll_immutable();
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS cats_active_idx;
+
+-- Removed cats used to be deleted right away
+DELETE FROM cats WHERE deleted_at IS NOT NULL;
+
+ALTER TABLE cats DROP COLUMN deleted_at;
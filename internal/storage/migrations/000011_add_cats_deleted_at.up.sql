@@ -0,0 +1,3 @@
+ALTER TABLE cats ADD COLUMN deleted_at TIMESTAMP;
+
+CREATE INDEX cats_active_idx ON cats (id) WHERE deleted_at IS NULL;
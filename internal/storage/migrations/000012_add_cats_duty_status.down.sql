@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS missions_active_cat_idx;
+
+ALTER TABLE cats DROP CONSTRAINT IF EXISTS cats_duty_status_check;
+ALTER TABLE cats DROP COLUMN duty_status;
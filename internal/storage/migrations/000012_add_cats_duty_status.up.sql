@@ -0,0 +1,7 @@
+ALTER TABLE cats ADD COLUMN duty_status VARCHAR(20) NOT NULL DEFAULT 'active';
+
+ALTER TABLE cats ADD CONSTRAINT cats_duty_status_check
+    CHECK (duty_status IN ('active', 'on_leave', 'retired'));
+
+CREATE INDEX missions_active_cat_idx ON missions (cat_id)
+    WHERE status IN ('assigned', 'in_progress');
@@ -0,0 +1,14 @@
+DROP TABLE IF EXISTS mission_assignments;
+
+DROP INDEX IF EXISTS missions_one_active_per_cat_idx;
+CREATE INDEX missions_active_cat_idx ON missions (cat_id)
+    WHERE status IN ('assigned', 'in_progress');
+
+-- Keep the cat on its latest mission only
+UPDATE missions m SET cat_id = NULL
+WHERE cat_id IS NOT NULL AND EXISTS (
+    SELECT 1 FROM missions newer
+    WHERE newer.cat_id = m.cat_id AND (newer.created_at, newer.id) > (m.created_at, m.id)
+);
+
+ALTER TABLE missions ADD CONSTRAINT missions_cat_id_key UNIQUE (cat_id);
@@ -0,0 +1,34 @@
+ALTER TABLE missions DROP CONSTRAINT IF EXISTS missions_cat_id_key;
+
+-- A cat can only work on one mission at a time
+DROP INDEX IF EXISTS missions_active_cat_idx;
+CREATE UNIQUE INDEX missions_one_active_per_cat_idx ON missions (cat_id)
+    WHERE status IN ('assigned', 'in_progress');
+
+CREATE TABLE mission_assignments (
+    id SERIAL PRIMARY KEY,
+    mission_id INT NOT NULL REFERENCES missions(id) ON DELETE CASCADE,
+    cat_id INT NOT NULL REFERENCES cats(id),
+    assigned_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    unassigned_at TIMESTAMP,
+    unassign_reason VARCHAR(20),
+    CONSTRAINT mission_assignments_reason_check
+        CHECK (unassign_reason IN ('unassigned', 'reassigned', 'cat_removed', 'completed', 'aborted'))
+);
+
+CREATE INDEX mission_assignments_mission_idx ON mission_assignments (mission_id, assigned_at);
+CREATE INDEX mission_assignments_cat_idx ON mission_assignments (cat_id, assigned_at);
+
+-- Only one assignment of a mission is open at a time
+CREATE UNIQUE INDEX mission_assignments_open_idx ON mission_assignments (mission_id)
+    WHERE unassigned_at IS NULL;
+
+-- Missions that already ended get a closed assignment
+INSERT INTO mission_assignments (mission_id, cat_id, assigned_at, unassigned_at, unassign_reason)
+SELECT id, cat_id, created_at,
+    CASE status
+        WHEN 'completed' THEN COALESCE(completed_at, NOW())
+        WHEN 'aborted' THEN NOW()
+    END,
+    CASE WHEN status IN ('completed', 'aborted') THEN status END
+FROM missions WHERE cat_id IS NOT NULL;
@@ -0,0 +1,12 @@
+ALTER TABLE targets ADD COLUMN notes TEXT;
+
+UPDATE targets t SET notes = n.notes
+FROM (
+    SELECT target_id, string_agg(body, E'\n' ORDER BY created_at, id) AS notes
+    FROM target_notes
+    GROUP BY target_id
+) n
+WHERE n.target_id = t.id;
+
+DROP TABLE IF EXISTS target_notes;
+DROP FUNCTION IF EXISTS target_notes_append_only();
@@ -0,0 +1,26 @@
+CREATE TABLE target_notes (
+    id SERIAL PRIMARY KEY,
+    target_id INT NOT NULL REFERENCES targets(id) ON DELETE CASCADE,
+    author VARCHAR(100) NOT NULL,
+    body TEXT NOT NULL CHECK (body <> ''),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX target_notes_target_idx ON target_notes (target_id, created_at);
+
+-- Notes are an append-only journal, they are only deleted along with their target
+CREATE FUNCTION target_notes_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'target notes are append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER target_notes_append_only
+    BEFORE UPDATE ON target_notes
+    FOR EACH ROW EXECUTE FUNCTION target_notes_append_only();
+
+INSERT INTO target_notes (target_id, author, body)
+SELECT id, 'unknown', notes FROM targets
+WHERE notes IS NOT NULL AND notes <> '';
+
+ALTER TABLE targets DROP COLUMN notes;
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS target_notes_search_idx;
+ALTER TABLE target_notes DROP COLUMN IF EXISTS search;
+
+DROP INDEX IF EXISTS targets_search_idx;
+ALTER TABLE targets DROP COLUMN IF EXISTS search;
@@ -0,0 +1,13 @@
+-- Names and countries are proper nouns, so they aren't stemmed
+ALTER TABLE targets ADD COLUMN search tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('simple', name), 'A') ||
+    setweight(to_tsvector('simple', country), 'B')
+) STORED;
+
+CREATE INDEX targets_search_idx ON targets USING GIN (search);
+
+ALTER TABLE target_notes ADD COLUMN search tsvector GENERATED ALWAYS AS (
+    to_tsvector('english', body)
+) STORED;
+
+CREATE INDEX target_notes_search_idx ON target_notes USING GIN (search);
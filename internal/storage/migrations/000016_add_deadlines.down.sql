@@ -0,0 +1,10 @@
+DROP TABLE IF EXISTS overdue_events;
+
+DROP INDEX IF EXISTS targets_due_at_idx;
+DROP INDEX IF EXISTS missions_due_at_idx;
+
+ALTER TABLE targets DROP COLUMN IF EXISTS overdue_at;
+ALTER TABLE targets DROP COLUMN IF EXISTS due_at;
+
+ALTER TABLE missions DROP COLUMN IF EXISTS overdue_at;
+ALTER TABLE missions DROP COLUMN IF EXISTS due_at;
@@ -0,0 +1,20 @@
+ALTER TABLE missions ADD COLUMN due_at TIMESTAMP;
+ALTER TABLE missions ADD COLUMN overdue_at TIMESTAMP;
+
+ALTER TABLE targets ADD COLUMN due_at TIMESTAMP;
+ALTER TABLE targets ADD COLUMN overdue_at TIMESTAMP;
+
+CREATE INDEX missions_due_at_idx ON missions (due_at) WHERE due_at IS NOT NULL;
+CREATE INDEX targets_due_at_idx ON targets (due_at) WHERE due_at IS NOT NULL AND NOT is_completed;
+
+-- A target_id marks a missed target deadline, otherwise the mission's own one
+CREATE TABLE overdue_events (
+    id SERIAL PRIMARY KEY,
+    mission_id INT NOT NULL REFERENCES missions(id) ON DELETE CASCADE,
+    target_id INT REFERENCES targets(id) ON DELETE CASCADE,
+    due_at TIMESTAMP NOT NULL,
+    detected_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX overdue_events_detected_at_idx ON overdue_events (detected_at);
+CREATE INDEX overdue_events_mission_idx ON overdue_events (mission_id);
@@ -0,0 +1,4 @@
+ALTER TABLE missions DROP COLUMN IF EXISTS template_id;
+
+DROP TABLE IF EXISTS template_targets;
+DROP TABLE IF EXISTS mission_templates;
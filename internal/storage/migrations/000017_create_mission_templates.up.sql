@@ -0,0 +1,22 @@
+-- Every update of a template adds a new version, older versions are kept
+-- for the missions created from them
+CREATE TABLE mission_templates (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    version INT NOT NULL CHECK (version >= 1),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT mission_templates_name_version_key UNIQUE (name, version)
+);
+
+CREATE TABLE template_targets (
+    id SERIAL PRIMARY KEY,
+    template_id INT NOT NULL REFERENCES mission_templates(id) ON DELETE CASCADE,
+    position INT NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    country VARCHAR(100) NOT NULL,
+    notes TEXT[] NOT NULL DEFAULT '{}',
+    CONSTRAINT template_targets_position_key UNIQUE (template_id, position),
+    CONSTRAINT template_targets_name_key UNIQUE (template_id, name)
+);
+
+ALTER TABLE missions ADD COLUMN template_id INT REFERENCES mission_templates(id) ON DELETE SET NULL;
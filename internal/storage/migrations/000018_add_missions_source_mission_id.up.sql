@@ -0,0 +1 @@
+ALTER TABLE missions ADD COLUMN source_mission_id INT REFERENCES missions(id) ON DELETE SET NULL;
@@ -0,0 +1,70 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"math/rand/v2"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const (
+	serializationFailure = "40001"
+	deadlockDetected     = "40P01"
+
+	txMaxAttempts    = 5
+	txRetryBaseDelay = 10 * time.Millisecond
+)
+
+// WithTx runs fn in a transaction and commits it, rolling back if fn fails.
+// Transactions aborted by a serialization failure or a deadlock are retried
+// with a jittered backoff, so fn must not have side effects outside of tx.
+func WithTx(ctx context.Context, db *sql.DB, opts *sql.TxOptions, fn func(tx *sql.Tx) error) error {
+	var err error
+	for attempt := 0; attempt < txMaxAttempts; attempt++ {
+		if attempt > 0 {
+			delay := txRetryBaseDelay << (attempt - 1)
+			delay += rand.N(delay)
+			slog.Warn("Retrying transaction", "attempt", attempt+1, "delay", delay, "error", err)
+
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(delay):
+			}
+		}
+
+		err = runTx(ctx, db, opts, fn)
+		if !retryable(err) {
+			return err
+		}
+	}
+
+	return err
+}
+
+func runTx(ctx context.Context, db *sql.DB, opts *sql.TxOptions, fn func(tx *sql.Tx) error) error {
+	tx, err := db.BeginTx(ctx, opts)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// retryable reports whether a transaction failed only because it ran concurrently with another.
+func retryable(err error) bool {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return false
+	}
+	return pqErr.Code == serializationFailure || pqErr.Code == deadlockDetected
+}